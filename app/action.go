@@ -0,0 +1,258 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"github.com/CloudyKit/framework/container"
+	"github.com/CloudyKit/framework/request"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	contextType         = reflect.TypeOf((*context.Context)(nil)).Elem()
+	registryType        = reflect.TypeOf((*container.Registry)(nil))
+	errorType           = reflect.TypeOf((*error)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Responder is a value returned by an action which writes itself into the response,
+// see Redirect, JSON and view.Template
+type Responder interface {
+	Respond(c *request.Context) error
+}
+
+// ResponderFunc func implementing Responder interface
+type ResponderFunc func(c *request.Context) error
+
+func (fn ResponderFunc) Respond(c *request.Context) error {
+	return fn(c)
+}
+
+// Redirect returns a Responder redirecting the request to urlStr with http.StatusFound
+func Redirect(urlStr string) Responder {
+	return RedirectStatus(urlStr, http.StatusFound)
+}
+
+// RedirectStatus returns a Responder redirecting the request to urlStr with the status code
+func RedirectStatus(urlStr string, status int) Responder {
+	return ResponderFunc(func(c *request.Context) error {
+		c.RedirectStatus(urlStr, status)
+		return nil
+	})
+}
+
+// JSON returns a Responder sending v encoded as json with the status code
+func JSON(status int, v interface{}) Responder {
+	return ResponderFunc(func(c *request.Context) error {
+		c.Response.Header().Set("Content-Type", "application/json")
+		c.Response.WriteHeader(status)
+		return json.NewEncoder(c.Response).Encode(v)
+	})
+}
+
+// respond writes the value returned by an action into the response, strings and bytes are
+// written as is, Responders respond by themselves and any other value is encoded as json
+func respond(c *request.Context, result interface{}) error {
+	switch result := result.(type) {
+	case nil:
+		return nil
+	case Responder:
+		return result.Respond(c)
+	case string:
+		_, err := c.WriteString(result)
+		return err
+	case []byte:
+		_, err := c.Response.Write(result)
+		return err
+	}
+	return JSON(http.StatusOK, result).Respond(c)
+}
+
+// actionArgument resolves an action argument for the request
+type actionArgument func(c *request.Context) (reflect.Value, error)
+
+// actionArguments builds the argument resolvers of the action func, arguments of basic types are
+// assigned from the route parameters in the order they appear in path, context.Context and *container.Registry
+// are the request ones, other types are loaded from the request registry, structs embedding request.Bindable
+// not available in the registry are allocated and bound from the request, see request.Context.Bind
+func actionArguments(action string, funcType reflect.Type, path string) []actionArgument {
+	if funcType.IsVariadic() {
+		panic(fmt.Errorf("action %s: variadic actions are not supported", action))
+	}
+
+	params := routeParameters(path)
+	arguments := make([]actionArgument, 0, funcType.NumIn()-1)
+
+	for i := 1; i < funcType.NumIn(); i++ {
+		argType := funcType.In(i)
+		switch {
+		case argType == contextType:
+			arguments = append(arguments, func(c *request.Context) (reflect.Value, error) {
+				return reflect.ValueOf(c.Context()), nil
+			})
+		case argType == registryType:
+			arguments = append(arguments, func(c *request.Context) (reflect.Value, error) {
+				return reflect.ValueOf(c.Registry), nil
+			})
+		case isRouteParameterType(argType):
+			if len(params) == 0 {
+				panic(fmt.Errorf("action %s: argument %d of type %s has no matching route parameter in %q", action, i, argType, path))
+			}
+			arguments = append(arguments, routeArgument(params[0], argType))
+			params = params[1:]
+		default:
+			arguments = append(arguments, registryArgument(argType))
+		}
+	}
+	return arguments
+}
+
+// routeParameters returns the parameter names in the route path
+func routeParameters(path string) (params []string) {
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" && (segment[0] == ':' || segment[0] == '*') {
+			params = append(params, segment[1:])
+		}
+	}
+	return
+}
+
+func isRouteParameterType(typ reflect.Type) bool {
+	if reflect.PtrTo(typ).Implements(textUnmarshalerType) && typ.Kind() != reflect.Struct {
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func routeArgument(name string, typ reflect.Type) actionArgument {
+	return func(c *request.Context) (reflect.Value, error) {
+		value := reflect.New(typ).Elem()
+		if err := parseRouteValue(c.GetURLParameter(name), value); err != nil {
+			return value, request.WrapError(http.StatusNotFound, fmt.Errorf("route parameter %q: %w", name, err))
+		}
+		return value, nil
+	}
+}
+
+func parseRouteValue(param string, value reflect.Value) error {
+	if unmarshaler, ok := value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(param))
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(param)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(param)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(param, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(param, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(param, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(f)
+	}
+	return nil
+}
+
+func registryArgument(typ reflect.Type) actionArgument {
+	structTyp := typ
+	if structTyp.Kind() == reflect.Ptr {
+		structTyp = structTyp.Elem()
+	}
+	bindable := request.IsBindable(typ)
+
+	return func(c *request.Context) (reflect.Value, error) {
+		value := reflect.New(typ)
+		c.Registry.LoadValue(value)
+		value = value.Elem()
+
+		if !value.IsZero() {
+			return value, nil
+		}
+		if !bindable {
+			return value, fmt.Errorf("action argument of type %s is not provided by the registry and doesn't embed request.Bindable", typ)
+		}
+
+		// the value is not provided by the registry, it is bound from the request
+		target := reflect.New(structTyp)
+		if err := c.Bind(target.Interface()); err != nil {
+			return value, err
+		}
+		if typ.Kind() == reflect.Ptr {
+			return target, nil
+		}
+		return target.Elem(), nil
+	}
+}
+
+// actionResults validates the action func results, returns the index of the value and error results or -1
+func actionResults(action string, funcType reflect.Type) (valueIndex, errorIndex int) {
+	valueIndex, errorIndex = -1, -1
+	switch funcType.NumOut() {
+	case 0:
+	case 1:
+		if funcType.Out(0) == errorType {
+			errorIndex = 0
+		} else {
+			valueIndex = 0
+		}
+	case 2:
+		if funcType.Out(1) != errorType {
+			panic(fmt.Errorf("action %s: the second result should be an error", action))
+		}
+		valueIndex, errorIndex = 0, 1
+	default:
+		panic(fmt.Errorf("action %s: actions can return at most a value and an error", action))
+	}
+	return
+}
@@ -0,0 +1,132 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"errors"
+	"github.com/CloudyKit/framework/request"
+	"github.com/CloudyKit/framework/tdutils"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type userService struct {
+	names map[int]string
+}
+
+type userForm struct {
+	request.Bindable
+	Name string
+}
+
+type userStats struct {
+	Total int
+}
+
+type usersController struct{}
+
+func (c *usersController) Mx(mx *Mapper) {
+	mx.BindAction("GET", "/users/:id/posts/:post", "Post")
+	mx.BindAction("GET", "/users/:id", "Show")
+	mx.BindAction("POST", "/users", "Create")
+	mx.BindAction("GET", "/old", "Old")
+	mx.BindAction("GET", "/unresolved", "Unresolved")
+}
+
+func (c *usersController) Post(id int, post string) string {
+	return strings.Repeat(post, id)
+}
+
+func (c *usersController) Show(id int, service *userService) (interface{}, error) {
+	name, found := service.names[id]
+	if !found {
+		return nil, request.NewError(http.StatusNotFound, "user not found")
+	}
+	return map[string]string{"name": name}, nil
+}
+
+func (c *usersController) Create(form *userForm, service *userService) error {
+	if form.Name == "" {
+		return errors.New("name is required")
+	}
+	service.names[len(service.names)+1] = form.Name
+	return nil
+}
+
+func (c *usersController) Unresolved(service *userStats) string {
+	return "unreachable"
+}
+
+func (c *usersController) Old() Responder {
+	return RedirectStatus("/users/1", http.StatusMovedPermanently)
+}
+
+func TestMapper_ActionArguments(t *testing.T) {
+	kernel := New()
+	kernel.WithErrorHandler(&ErrorHandler{Logger: log.New(io.Discard, "", 0)})
+
+	service := &userService{names: map[int]string{1: "carla"}}
+	kernel.Registry.WithValues(service)
+	kernel.AddControllers(&usersController{})
+
+	tester := tdutils.NewHTTPTester(t, kernel)
+
+	tester.GetRequest("/users/3/posts/ab").ExpectOutput("ababab", "route parameters should be assigned in order")
+	tester.GetRequest("/users/1").ExpectOutput("{\"name\":\"carla\"}\n", "values should be sent as json")
+	tester.GetRequest("/users/2").ExpectStatus(http.StatusNotFound, "returned errors should be sent to the error handler")
+	tester.GetRequest("/users/abc").ExpectStatus(http.StatusNotFound, "invalid route parameters should respond with not found")
+	tester.GetRequest("/unresolved").ExpectStatus(http.StatusInternalServerError, "structs not provided by the registry and not bindable should not resolve")
+	tester.GetRequest("/old").ExpectRedirect("/users/1", "responders should write the response").
+		ExpectStatus(http.StatusMovedPermanently, "redirect status should be used")
+
+	r, _ := http.NewRequest("POST", "/users", strings.NewReader("Name=shinji"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	tester.Request(r).ExpectStatus(http.StatusOK, "form should be bound from the request")
+	if service.names[2] != "shinji" {
+		t.Errorf("want bound name shinji got %q", service.names[2])
+	}
+
+	r, _ = http.NewRequest("POST", "/users", strings.NewReader(`{}`))
+	r.Header.Set("Content-Type", "application/json")
+	tester.Request(r).ExpectStatus(http.StatusInternalServerError, "returned error should respond with status 500")
+}
+
+type invalidController struct{}
+
+func (c *invalidController) Mx(mx *Mapper) {
+	mx.BindAction("GET", "/invalid", "Invalid")
+}
+
+func (c *invalidController) Invalid(id int) {}
+
+func TestMapper_ActionArgumentsWithoutParameter(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("binding an action with more arguments than route parameters should panic")
+		}
+	}()
+	New().AddControllers(&invalidController{})
+}
@@ -34,6 +34,8 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var KernelType = reflect.TypeOf((*Kernel)(nil))
@@ -45,15 +47,19 @@ func GetKernel(c *container.Registry) *Kernel {
 var Default = New()
 
 func New() *Kernel {
-	kernel := &Kernel{Registry: container.New(), Router: router.New(), URLGen: make(MapURLGen), emitter: event.NewDispatcher()}
+	kernel := &Kernel{Registry: container.New(), Router: router.New(), URLGen: make(MapURLGen), URLs: NewURLBuilder(""), routes: new(routeTable), hosts: new(hostTable), lifecycle: new(lifecycle), emitter: event.NewDispatcher()}
 
 	// provide service URLGen as URLer
 	kernel.Registry.WithTypeAndValue(common.URLGenType, kernel.URLGen)
+	// provide the named parameters url builder
+	kernel.Registry.WithTypeAndValue(URLBuilderType, kernel.URLs)
 	// provide the Router
 	kernel.Registry.WithValues(kernel.Router)
 	// provide the app
 	kernel.Registry.WithTypeAndValue(KernelType, kernel)
 	kernel.Registry.WithTypeAndValue(event.EmitterType, kernel.emitter)
+	// provide the default error handler
+	kernel.Registry.WithTypeAndValue(request.ErrorHandlerType, &ErrorHandler{})
 
 	return kernel
 }
@@ -88,6 +94,11 @@ func (filterHandlers *filterHandlers) BindFilterFuncHandlers(filters ...request.
 	filterHandlers.filters = newFilters
 }
 
+// BindMiddlewares binds standard net/http middlewares as filters, see request.WrapMiddleware
+func (filterHandlers *filterHandlers) BindMiddlewares(middlewares ...func(http.Handler) http.Handler) {
+	filterHandlers.BindFilterHandlers(request.WrapMiddlewares(middlewares...)...)
+}
+
 func (filterHandlers *filterHandlers) reSlice(filters ...request.Handler) []request.Handler {
 	newFilter := make([]request.Handler, 0, len(filterHandlers.filters)+len(filters))
 	newFilter = append(newFilter, filterHandlers.filters...)
@@ -110,6 +121,21 @@ type Kernel struct {
 	Router   *router.Router      // Router
 	Prefix   string              // Prefix prefix for path added in this app
 	URLGen   MapURLGen
+	URLs     *URLBuilder // URLs reverse routing with named parameters
+
+	NamePrefix  string // NamePrefix prefix for route names and URLGen keys added in this app, see Kernel.NamedGroup
+	HostPattern string // HostPattern host matched by the routes added in this app, see Kernel.Host
+
+	NotFound         request.Handler // NotFound handles requests without a matching route, see Kernel.ServeHTTP
+	MethodNotAllowed request.Handler // MethodNotAllowed handles requests matching a route of a different method
+
+	Server          ServerOptions // Server settings used by Serve, ServeTLS, RunServer and RunServerTLS
+	ShutdownTimeout time.Duration // ShutdownTimeout time given to in-flight requests on shutdown
+
+	routes    *routeTable
+	hosts     *hostTable
+	lifecycle *lifecycle
+	disposed  int32
 	filterHandlers
 }
 
@@ -143,13 +169,17 @@ func (component ComponentFunc) Bootstrap(a *Kernel) {
 	component(a)
 }
 
-// Bootstrap bootstraps a list of components, a sub scope will be created, and a copy of the
-// original app is used, in such form that modifying the app.Prefix will not reflect outside this
-// call.
+// Bootstrap bootstraps a list of components, each component receives its own group of the kernel,
+// see Kernel.Group, in such form that modifying the Prefix or the filters will not reflect outside
+// the component. Components required by other components in the list are bootstrapped first, see Requirer,
+// the components are recorded to be started and stopped with the kernel, see Kernel.Start
 func (kernel *Kernel) Bootstrap(b ...Component) {
-	newApp := kernel.Fork()
-	prefix := newApp.Prefix
+	// errors are reported by Kernel.Start, requirements can be bootstrapped in a later call
+	b, _ = sortComponents(b, false)
+	kernel.lifecycle.add(b...)
+
 	for i := 0; i < len(b); i++ {
+		newApp := kernel.Group("")
 
 		bv := reflect.ValueOf(b[i])
 		if bv.Kind() == reflect.Ptr {
@@ -160,13 +190,15 @@ func (kernel *Kernel) Bootstrap(b ...Component) {
 		}
 
 		b[i].Bootstrap(newApp)
-		newApp.Prefix = prefix
 	}
 }
 
-// Dispose End same as app.Registry.End() invoke this func before exiting the app to cleanup
+// Dispose End same as app.Registry.End() invoke this func before exiting the app to cleanup,
+// the kernel is provided by its own registry, so only the first call disposes the registry
 func (kernel *Kernel) Dispose() {
-	kernel.Registry.Dispose()
+	if atomic.CompareAndSwapInt32(&kernel.disposed, 0, 1) {
+		kernel.Registry.Dispose()
+	}
 }
 
 // AddHandlerFunc register a func handler, see: request.Handler
@@ -180,7 +212,12 @@ func (kernel *Kernel) AddHandler(method, path string, handler request.Handler, f
 }
 
 // AddHandlerName register a named handler, see: request.Handler
+// named handlers are also registered in the URLGen and in the URLBuilder with the key NamePrefix+name, as
+// the controller actions are, see Kernel.URLs
 func (kernel *Kernel) AddHandlerName(name, method, path string, handler request.Handler, filters ...request.Handler) {
+	if name != "" {
+		kernel.addURL(kernel.NamePrefix+name, kernel.Prefix+path)
+	}
 	kernel.AddHandlerContextName(kernel.Registry, name, method, path, handler, filters...)
 }
 
@@ -196,18 +233,39 @@ func (kernel *Kernel) AddHandlerContextName(registry *container.Registry, name,
 		registry = kernel.Registry
 	}
 
+	if name != "" {
+		name = kernel.NamePrefix + name
+	}
+
+	route := Route{Host: kernel.HostPattern, Path: kernel.Prefix + path, Name: name, Filters: handlerNames(filters[:len(filters)-1])}
+	if controller, isController := handler.(*controllerHandler); isController {
+		route.Controller, route.Action = controller.controller, controller.action
+	} else {
+		route.Handler = request.HandlerName(handler)
+	}
+
 	for _, method := range strings.Split(method, "|") {
+		route.Method = method
+		kernel.routes.add(route)
 		kernel.Router.AddRoute(method, kernel.Prefix+path, func(rw http.ResponseWriter, r *http.Request, v router.Parameter) {
 			c := newRequestContext()
 			defer requestRecover(c)
+			c.Routed = true
 			_ = request.DispatchNext(c, name, rw, r, v, registry.Fork(), filters)
 		})
 	}
 }
 
-// requestRecover finalizes and cleanup request allocated scope variables
+// requestRecover recovers panics raised in the request flow sending them to the error handler,
+// finalizes and cleanup request allocated scope variables
 func requestRecover(c *request.Context) {
 
+	recovered := recover()
+	if recovered != nil && recovered != http.ErrAbortHandler && c.Registry != nil {
+		handlePanic(c, recovered)
+		recovered = nil
+	}
+
 	variables := c.Registry
 	// resets request context
 	*c = request.Context{}
@@ -215,7 +273,13 @@ func requestRecover(c *request.Context) {
 
 	// we call scope EndForce, this requires that all children scopes Ended in this call if not
 	// panic is raised
-	variables.MustDispose()
+	if variables != nil {
+		variables.MustDispose()
+	}
+
+	if recovered != nil {
+		panic(recovered)
+	}
 }
 
 func (kernel *Kernel) host(host string) (servein string) {
@@ -232,20 +296,22 @@ func (kernel *Kernel) host(host string) (servein string) {
 	return
 }
 
-// RunServer runs the server with the specified host
-// Calling this func will emit a "app.run" event in the app
+// RunServer runs the server with the specified host, the server is shutdown gracefully
+// when the process receives SIGINT or SIGTERM, see Kernel.Serve
+// Calling this func will emit a "hub.run" event in the app
 func (kernel *Kernel) RunServer(host string) error {
-	e := &RunServerEvent{Host: host}
-	kernel.Dispatch("hub.run", e)
-	return http.ListenAndServe(host, kernel.Router)
+	ctx, cancel := signalContext()
+	defer cancel()
+	return kernel.Serve(ctx, host)
 }
 
-// RunServerTLS runs the server in tls mode
-// Calling this func will emit a "app.run.tls" event in the app
+// RunServerTLS runs the server in tls mode, the server is shutdown gracefully
+// when the process receives SIGINT or SIGTERM, see Kernel.ServeTLS
+// Calling this func will emit a "hub.run.tls" event in the app
 func (kernel *Kernel) RunServerTLS(host, certfile, keyfile string) error {
-	e := &RunServerEventTLS{Host: host, CertFile: certfile, KeyFile: keyfile}
-	kernel.Dispatch("hub.run.tls", e)
-	return http.ListenAndServeTLS(kernel.host(host), certfile, keyfile, kernel.Router)
+	ctx, cancel := signalContext()
+	defer cancel()
+	return kernel.ServeTLS(ctx, host, certfile, keyfile)
 }
 
 func (kernel *Kernel) Subscribe(eventName string, handler interface{}) {
@@ -0,0 +1,111 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/CloudyKit/framework/console"
+	"io"
+	"os"
+)
+
+// AddCommand adds a console command to the kernel, the exported fields of the command are injected
+// from the kernel registry when the command runs, see console.Command and Kernel.RunCLI
+func (kernel *Kernel) AddCommand(name, usage string, command console.Command) {
+	console.GetCommands(kernel.Registry).Add(name, usage, command)
+}
+
+// RunCLI runs the command line args, args includes the program name, ex: os.Args. The components are
+// bootstrapped before the command line is parsed, so the command fields are injected from the bootstrapped
+// registry. The components are started before the command runs and stopped after it returns, the kernel
+// is disposed before RunCLI returns. The context of the command is canceled when the process receives
+// SIGINT or SIGTERM. The exit code is returned, ex: os.Exit(kernel.RunCLI(os.Args, &odm.Component{})).
+// The command routes is added when not defined, see RoutesCommand
+func (kernel *Kernel) RunCLI(args []string, components ...Component) (code int) {
+	defer kernel.Dispose()
+	kernel.Bootstrap(components...)
+
+	commands := console.GetCommands(kernel.Registry)
+	if _, found := commands.Lookup("routes"); !found {
+		commands.Add("routes", "Prints the registered routes\n"+
+			"The routes are printed in registration order, -check fails when routes conflict", &RoutesCommand{})
+	}
+	invocation, err := commands.Parse(kernel.Registry, args)
+	if err != nil {
+		return commands.Exit(err)
+	}
+	defer invocation.Dispose()
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	if err := kernel.Start(ctx); err != nil {
+		return commands.Exit(err)
+	}
+	defer func() {
+		timeout := kernel.ShutdownTimeout
+		if timeout <= 0 {
+			timeout = DefaultShutdownTimeout
+		}
+		stopCtx, stopCancel := context.WithTimeout(context.Background(), timeout)
+		defer stopCancel()
+		code = commands.Exit(errors.Join(err, kernel.Stop(stopCtx)))
+	}()
+
+	err = invocation.Run(ctx)
+	return
+}
+
+// RoutesCommand the routes console command, prints the kernel routes to the commands Stdout, see Routes.String
+type RoutesCommand struct {
+	Kernel   *Kernel
+	Commands *console.Commands
+
+	JSON  bool `flag:"json" usage:"prints the routes as json"`
+	Check bool `flag:"check" usage:"fails when routes conflict, see Kernel.CheckRoutes"`
+}
+
+func (command *RoutesCommand) Run(_ context.Context) error {
+	var out io.Writer = os.Stdout
+	if command.Commands != nil && command.Commands.Stdout != nil {
+		out = command.Commands.Stdout
+	}
+
+	routes := command.Kernel.Routes()
+	if command.JSON {
+		data, err := routes.JSON()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s\n", data)
+	} else {
+		fmt.Fprint(out, routes.String())
+	}
+
+	if command.Check {
+		return command.Kernel.CheckRoutes()
+	}
+	return nil
+}
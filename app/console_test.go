@@ -0,0 +1,105 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"github.com/CloudyKit/framework/console"
+	"github.com/CloudyKit/framework/request"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type greeterService struct {
+	log *lifecycleLog
+}
+
+type greetCommand struct {
+	Greeter *greeterService
+
+	Name string `arg:"name" default:"world"`
+}
+
+func (command *greetCommand) Run(ctx context.Context) error {
+	*command.Greeter.log = append(*command.Greeter.log, "greet "+command.Name)
+	return nil
+}
+
+func TestKernel_RunCLI(t *testing.T) {
+	var log lifecycleLog
+	// the greeter injected in the command is provided by a component bootstrapped by RunCLI
+	greeter := ComponentFunc(func(kernel *Kernel) {
+		kernel.Registry.WithValues(&greeterService{log: &log})
+	})
+	newKernel := func() *Kernel {
+		kernel := New()
+		kernel.AddCommand("greet", "Greets someone", &greetCommand{})
+		console.GetCommands(kernel.Registry).Stderr = new(bytes.Buffer)
+		return kernel
+	}
+
+	if code := newKernel().RunCLI([]string{"app", "greet", "gopher"}, greeter, &databaseComponent{log: &log}); code != console.ExitOK {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+
+	expected := lifecycleLog{"bootstrap database", "start database", "greet gopher", "stop database"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("unexpected command lifecycle:\n got %v\nwant %v", log, expected)
+	}
+
+	// components are not started for invalid command lines
+	log = nil
+	if code := newKernel().RunCLI([]string{"app", "greet", "a", "b"}, greeter, &databaseComponent{log: &log}); code != console.ExitUsage {
+		t.Errorf("expected usage exit code, got %d", code)
+	}
+	if !reflect.DeepEqual(log, lifecycleLog{"bootstrap database"}) {
+		t.Errorf("unexpected lifecycle %v", log)
+	}
+}
+
+func TestKernel_RoutesCommand(t *testing.T) {
+	var stdout bytes.Buffer
+	newKernel := func() *Kernel {
+		kernel := New()
+		kernel.AddHandlerFunc("GET", "/users/:id", func(c *request.Context) {})
+		commands := console.GetCommands(kernel.Registry)
+		commands.Stdout = &stdout
+		commands.Stderr = new(bytes.Buffer)
+		return kernel
+	}
+
+	if code := newKernel().RunCLI([]string{"app", "routes", "-check"}); code != console.ExitOK {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	if !strings.Contains(stdout.String(), "/users/:id") {
+		t.Errorf("routes are missing from the output:\n%s", stdout.String())
+	}
+
+	kernel := newKernel()
+	kernel.AddHandlerFunc("GET", "/users/new", func(c *request.Context) {})
+	if code := kernel.RunCLI([]string{"app", "routes", "-check"}); code != console.ExitFailure {
+		t.Errorf("conflicting routes should fail the check, got exit code %d", code)
+	}
+}
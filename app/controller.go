@@ -28,7 +28,6 @@ import (
 	"github.com/CloudyKit/framework/event"
 	"github.com/CloudyKit/framework/request"
 	"reflect"
-	"regexp"
 	"sync"
 )
 
@@ -49,10 +48,16 @@ type (
 	}
 
 	controllerHandler struct {
-		pool      *sync.Pool
-		isPtr     bool
-		funcValue reflect.Value
-		zeroValue reflect.Value
+		controller string
+		action     string
+		pool       *sync.Pool
+		isPtr      bool
+		funcValue  reflect.Value
+		zeroValue  reflect.Value
+
+		arguments  []actionArgument
+		valueIndex int
+		errorIndex int
 	}
 
 	Controller interface {
@@ -106,7 +111,7 @@ func (kernel *Kernel) AddControllers(contexts ...Controller) {
 		}
 
 		controller.Mx(mapper)
-		myURLGen.id = mapper.Name + "."
+		myURLGen.id = kernel.NamePrefix + mapper.Name + "."
 	}
 }
 
@@ -115,6 +120,11 @@ func (handler *controllerHandler) Handle(c *request.Context) {
 
 	// gets or allocates a new context
 	ctx := reflect.ValueOf(ii)
+	// the controller is released in a defer, so a panicking action still returns it to the pool
+	defer func() {
+		ctx.Elem().Set(handler.zeroValue)
+		handler.pool.Put(ii)
+	}()
 	c.Registry.InjectValue(ctx.Elem())
 
 	var arguments = [1]reflect.Value{ctx}
@@ -122,14 +132,50 @@ func (handler *controllerHandler) Handle(c *request.Context) {
 		arguments[0] = arguments[0].Elem()
 	}
 
-	handler.funcValue.Call(arguments[0:])
+	if len(handler.arguments) == 0 {
+		handler.respond(c, handler.funcValue.Call(arguments[0:]))
+	} else if actionArguments, err := handler.resolveArguments(c, arguments[0]); err != nil {
+		c.Error(err)
+	} else {
+		handler.respond(c, handler.funcValue.Call(actionArguments))
+	}
+}
+
+// String names the handler as controller.action, ex: in the tracing spans
+func (handler *controllerHandler) String() string {
+	return handler.controller + "." + handler.action
+}
 
-	ctx.Elem().Set(handler.zeroValue)
-	handler.pool.Put(ii)
+// resolveArguments resolves the action arguments, receiver is the controller value
+func (handler *controllerHandler) resolveArguments(c *request.Context, receiver reflect.Value) ([]reflect.Value, error) {
+	arguments := make([]reflect.Value, len(handler.arguments)+1)
+	arguments[0] = receiver
+	for i, argument := range handler.arguments {
+		value, err := argument(c)
+		if err != nil {
+			return nil, err
+		}
+		arguments[i+1] = value
+	}
+	return arguments, nil
 }
 
-var acRegex = regexp.MustCompile("/[:*][^/]+")
+// respond converts the action results into the response, errors are sent to the error handler
+func (handler *controllerHandler) respond(c *request.Context, results []reflect.Value) {
+	if handler.errorIndex != -1 && !results[handler.errorIndex].IsNil() {
+		c.Error(results[handler.errorIndex].Interface().(error))
+		return
+	}
+	if handler.valueIndex != -1 {
+		if err := respond(c, results[handler.valueIndex].Interface()); err != nil {
+			c.Error(err)
+		}
+	}
+}
 
+// BindAction binds the controller action to the route, actions can receive route parameters, request bound
+// structs embedding request.Bindable and values from the request registry, and return a value and an error which are sent as the response,
+// ex: func (c *Users) Show(id int, service *UserService) (interface{}, error)
 func (mx *Mapper) BindAction(method, path, action string, filters ...request.Handler) {
 	methodByName, isPtr := mx.typ.MethodByName(action)
 	if !isPtr {
@@ -139,17 +185,24 @@ func (mx *Mapper) BindAction(method, path, action string, filters ...request.Han
 		}
 	}
 
-	mx.app.URLGen[mx.Name+"."+action] = acRegex.ReplaceAllStringFunc(mx.app.Prefix+mx.Prefix+path, func(st string) string {
-		if st[1] == '*' {
-			return "%v"
-		}
-		return "/%v"
-	})
-
-	mx.app.AddHandlerContextName(mx.Registry, mx.Name, method, mx.Prefix+path, &controllerHandler{
-		pool:      mx.pool,
-		isPtr:     isPtr,
-		zeroValue: mx.zeroValue,
-		funcValue: methodByName.Func,
-	}, mx.reSlice(filters...)...)
+	fullPath := mx.app.Prefix + mx.Prefix + path
+	mx.app.addURL(mx.app.NamePrefix+mx.Name+"."+action, fullPath)
+
+	handler := &controllerHandler{
+		controller: mx.Name,
+		action:     action,
+		pool:       mx.pool,
+		isPtr:      isPtr,
+		zeroValue:  mx.zeroValue,
+		funcValue:  methodByName.Func,
+		arguments:  actionArguments(mx.Name+"."+action, methodByName.Type, fullPath),
+	}
+	handler.valueIndex, handler.errorIndex = actionResults(mx.Name+"."+action, methodByName.Type)
+
+	mx.app.AddHandlerContextName(mx.Registry, mx.Name, method, mx.Prefix+path, handler, mx.reSlice(filters...)...)
+}
+
+// WithErrorHandler replaces the error handler used by the actions of this controller
+func (mx *Mapper) WithErrorHandler(handler request.ErrorHandler) {
+	mx.Registry.WithTypeAndValue(request.ErrorHandlerType, handler)
 }
@@ -0,0 +1,139 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/CloudyKit/framework/request"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// ErrorHandler is the default request.ErrorHandler provided by the kernel, errors are logged
+// with the route name and sent as json to api clients or rendered by Pages otherwise
+type ErrorHandler struct {
+	Logger       *log.Logger          // Logger defaults to the request slog logger, see request.Context.Logger
+	Statuses     []ErrorStatus        // Statuses status codes of errors, the first entry matching the error is used
+	JSONPrefixes []string             // JSONPrefixes path prefixes of api routes, responses are always json
+	Pages        request.ErrorHandler // Pages renders html error responses, ex: view.ErrorPages
+}
+
+// ErrorStatus the status code of the errors matching Err with errors.Is, see ErrorHandler.Statuses
+type ErrorStatus struct {
+	Err    error
+	Status int
+}
+
+type errorResponse struct {
+	Status        int    `json:"status,omitempty"`
+	StatusMessage string `json:"status_message,omitempty"`
+}
+
+// Status returns the status code for err, see request.StatusOf
+func (handler *ErrorHandler) Status(err error) int {
+	for _, status := range handler.Statuses {
+		if errors.Is(err, status.Err) {
+			return status.Status
+		}
+	}
+	return request.StatusOf(err)
+}
+
+func (handler *ErrorHandler) wantsJSON(c *request.Context) bool {
+	for _, prefix := range handler.JSONPrefixes {
+		if strings.HasPrefix(c.Request.URL.Path, prefix) {
+			return true
+		}
+	}
+	if strings.HasPrefix(c.Response.Header().Get("Content-Type"), "application/json") {
+		return true
+	}
+	accept := c.Request.Header.Get("Accept")
+	return strings.Contains(accept, "json") && !strings.Contains(accept, "text/html")
+}
+
+func (handler *ErrorHandler) log(c *request.Context, status int, err error) {
+	if status < http.StatusInternalServerError {
+		return
+	}
+	var panicErr *request.PanicError
+	isPanic := errors.As(err, &panicErr)
+
+	logger := handler.Logger
+	if logger == nil {
+		attrs := []any{"route", c.Name, "method", c.Request.Method, "path", c.Request.URL.Path, "status", status, "error", err}
+		if isPanic {
+			attrs = append(attrs, "stack", string(panicErr.Stack))
+		}
+		c.Logger().Error("request error", attrs...)
+		return
+	}
+
+	logger.Printf("route %q %s %s: %v", c.Name, c.Request.Method, c.Request.URL.Path, err)
+	if isPanic {
+		logger.Printf("%s", panicErr.Stack)
+	}
+}
+
+func (handler *ErrorHandler) HandleError(c *request.Context, err error) {
+	status := handler.Status(err)
+	handler.log(c, status, err)
+
+	message := http.StatusText(status)
+	var statusErr *request.Error
+	if errors.As(err, &statusErr) && statusErr.Message != "" {
+		message = statusErr.Message
+	}
+
+	if handler.wantsJSON(c) {
+		c.Response.Header().Set("Content-Type", "application/json")
+		c.Response.WriteHeader(status)
+		_ = json.NewEncoder(c.Response).Encode(&errorResponse{Status: status, StatusMessage: message})
+		return
+	}
+
+	if handler.Pages != nil {
+		handler.Pages.HandleError(c, &request.Error{Status: status, Message: message, Err: err})
+		return
+	}
+
+	http.Error(c.Response, message, status)
+}
+
+// WithErrorHandler replaces the error handler used by the routes of this kernel
+func (kernel *Kernel) WithErrorHandler(handler request.ErrorHandler) {
+	kernel.Registry.WithTypeAndValue(request.ErrorHandlerType, handler)
+}
+
+// handlePanic sends the recovered value to the error handler of the request, a panic raised by the
+// error handler is only logged as at this point the response can't be recovered.
+func handlePanic(c *request.Context, recovered interface{}) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			c.Logger().Error("error handler panic", "route", c.Name, "panic", recovered)
+		}
+	}()
+	c.Error(request.NewPanicError(recovered))
+}
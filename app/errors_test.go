@@ -0,0 +1,100 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"github.com/CloudyKit/framework/request"
+	"github.com/CloudyKit/framework/tdutils"
+	"io"
+	"log"
+	"net/http"
+	"testing"
+)
+
+var errNotAllowed = errors.New("not allowed")
+
+// errLocked matches errNotAllowed as well, the first matching status is used
+var errLocked = fmt.Errorf("locked: %w", errNotAllowed)
+
+type errorController struct {
+	*request.Context
+}
+
+func (c *errorController) Mx(mx *Mapper) {
+	mx.WithErrorHandler(request.ErrorHandlerFunc(func(c *request.Context, err error) {
+		c.Response.WriteHeader(http.StatusTeapot)
+		c.WriteString("controller: " + err.Error())
+	}))
+	mx.BindAction("GET", "/controller", "Fail")
+}
+
+func (c *errorController) Fail() {
+	panic(errors.New("boom"))
+}
+
+func TestKernel_ErrorHandler(t *testing.T) {
+	kernel := New()
+	kernel.WithErrorHandler(&ErrorHandler{
+		Logger:       log.New(io.Discard, "", 0),
+		Statuses:     []ErrorStatus{{Err: errLocked, Status: http.StatusLocked}, {Err: errNotAllowed, Status: http.StatusForbidden}},
+		JSONPrefixes: []string{"/api"},
+	})
+
+	kernel.AddHandlerFunc("GET", "/panic", func(c *request.Context) {
+		panic("unexpected")
+	})
+	kernel.AddHandlerFunc("GET", "/api/missing", func(c *request.Context) {
+		c.Error(request.NewError(http.StatusNotFound, "user not found"))
+	})
+	kernel.AddHandlerFunc("GET", "/forbidden", func(c *request.Context) {
+		c.Error(errNotAllowed)
+	})
+	kernel.AddHandlerFunc("GET", "/locked", func(c *request.Context) {
+		c.Error(errLocked)
+	})
+	kernel.AddControllers(&errorController{})
+
+	tester := tdutils.NewHTTPTester(t, kernel.Router)
+
+	tester.GetRequest("/panic").
+		ExpectStatus(http.StatusInternalServerError, "panic should respond with status 500").
+		ExpectOutput("Internal Server Error\n", "unexpected panic output")
+
+	tester.GetRequest("/api/missing").
+		ExpectStatus(http.StatusNotFound, "request.Error status should be used").
+		ExpectOutput("{\"status\":404,\"status_message\":\"user not found\"}\n", "api routes should respond with json")
+
+	tester.GetRequest("/forbidden").
+		ExpectStatus(http.StatusForbidden, "mapped errors should use the configured status")
+
+	for i := 0; i < 10; i++ {
+		tester.GetRequest("/locked").
+			ExpectStatus(http.StatusLocked, "errors matching several statuses should use the first one")
+	}
+
+	tester.GetRequest("/controller").
+		ExpectStatus(http.StatusTeapot, "controller error handler should override the kernel handler").
+		ExpectOutputContains("controller: panic: boom", "unexpected controller error output")
+}
@@ -1,16 +1,33 @@
 package app
 
-import "github.com/CloudyKit/framework/event"
+import (
+	"context"
+	"github.com/CloudyKit/framework/event"
+	"net/http"
+)
 
+// RunServerEvent is dispatched as "hub.run" before the server starts, subscribers can
+// inspect or adjust the Options used to build the server
 type RunServerEvent struct {
 	event.Event
-	Host string
-	Port string
+	Host    string
+	Port    string
+	Options *ServerOptions
 }
 
+// RunServerEventTLS is dispatched as "hub.run.tls" before the server starts in tls mode
 type RunServerEventTLS struct {
 	event.Event
 	Host     string
 	CertFile string
 	KeyFile  string
+	Options  *ServerOptions
+}
+
+// ShutdownEvent is dispatched as "hub.shutdown" after the server stopped accepting
+// requests and the in-flight requests were drained, Context carries the shutdown deadline
+type ShutdownEvent struct {
+	event.Event
+	Server  *http.Server
+	Context context.Context
 }
@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"github.com/CloudyKit/framework/request"
+	"github.com/CloudyKit/router"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ServeHTTP dispatches the request to the matching route, unmatched requests are dispatched to
+// Kernel.MethodNotAllowed or Kernel.NotFound through the kernel filters like any other route.
+// Requests matching the path of routes of other methods are always answered with the Allow header,
+// case MethodNotAllowed is not set a plain 405 response is sent, case NotFound is not set the router
+// default response is sent
+func (kernel *Kernel) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	hostPattern := ""
+	if host, params := kernel.hosts.match(r.Host); host != nil {
+		if len(params) > 0 {
+			r = request.WithHostParameters(r, params)
+		}
+		if handler, parameter := host.router.FindRoute(r.Method, r.URL.Path); handler != nil {
+			handler(rw, r, parameter)
+			return
+		}
+		hostPattern = host.pattern
+	}
+
+	handler, parameter := kernel.Router.FindRoute(r.Method, r.URL.Path)
+	if handler != nil {
+		handler(rw, r, parameter)
+		return
+	}
+
+	if allowed := kernel.allowedMethods(hostPattern, r.URL.Path); len(allowed) > 0 {
+		rw.Header().Set("Allow", strings.Join(allowed, ", "))
+		if kernel.MethodNotAllowed == nil {
+			http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		kernel.dispatchFallback("MethodNotAllowed", http.StatusMethodNotAllowed, kernel.MethodNotAllowed, rw, r)
+		return
+	}
+
+	if kernel.NotFound != nil {
+		kernel.dispatchFallback("NotFound", http.StatusNotFound, kernel.NotFound, rw, r)
+		return
+	}
+
+	kernel.Router.ServeHTTP(rw, r)
+}
+
+// allowedMethods returns the methods with a route matching path, in the host routes or in the routes without host,
+// only the methods registered for the host and for the routes without host are looked up, see routeTable.add
+func (kernel *Kernel) allowedMethods(hostPattern, path string) (allowed []string) {
+	common, host := kernel.routes.hostMethods(hostPattern)
+	for _, method := range common {
+		if handler, _ := kernel.Router.FindRoute(method, path); handler != nil {
+			allowed = append(allowed, method)
+		}
+	}
+	for _, method := range host {
+		if i := sort.SearchStrings(allowed, method); i < len(allowed) && allowed[i] == method {
+			continue
+		}
+		if handler, _ := kernel.hosts.router(hostPattern).FindRoute(method, path); handler != nil {
+			allowed = append(allowed, method)
+			sort.Strings(allowed)
+		}
+	}
+	return
+}
+
+// dispatchFallback dispatches handler with a forked registry after the kernel filters,
+// status is sent case the handler writes the response without setting a status
+func (kernel *Kernel) dispatchFallback(name string, status int, handler request.Handler, rw http.ResponseWriter, r *http.Request) {
+	c := newRequestContext()
+	defer requestRecover(c)
+	_ = request.DispatchNext(c, name, &request.StatusWriter{ResponseWriter: rw, Default: status}, r, router.Parameter{}, kernel.Registry.Fork(), kernel.reSlice(handler))
+}
@@ -0,0 +1,66 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"github.com/CloudyKit/framework/request"
+	"github.com/CloudyKit/framework/tdutils"
+	"net/http"
+	"testing"
+)
+
+func TestKernel_NotFoundHandlers(t *testing.T) {
+	kernel := New()
+	kernel.BindFilterHandlers(writeFilter("layout "))
+
+	kernel.AddHandlerFunc("GET|POST", "/users/:id", func(c *request.Context) {})
+
+	tester := tdutils.NewHTTPTester(t, kernel)
+	tester.GetRequest("/missing").ExpectStatus(http.StatusNotFound, "router default should be used when NotFound is not set")
+
+	r, _ := http.NewRequest("DELETE", "/users/10", nil)
+	response := tester.Request(r).ExpectStatus(http.StatusMethodNotAllowed, "405 should be sent when MethodNotAllowed is not set")
+	if allow := response.Header().Get("Allow"); allow != "GET, POST" {
+		t.Errorf("want Allow header %q got %q", "GET, POST", allow)
+	}
+
+	kernel.NotFound = request.HandlerFunc(func(c *request.Context) {
+		c.WriteString(c.Name)
+	})
+	kernel.MethodNotAllowed = request.HandlerFunc(func(c *request.Context) {
+		c.WriteString(c.Name)
+	})
+
+	tester.GetRequest("/missing").
+		ExpectStatus(http.StatusNotFound, "not found handler should respond with status 404").
+		ExpectOutput("layout NotFound", "not found handler should run after the kernel filters")
+
+	r, _ = http.NewRequest("DELETE", "/users/10", nil)
+	response = tester.Request(r).
+		ExpectStatus(http.StatusMethodNotAllowed, "method not allowed handler should respond with status 405").
+		ExpectOutput("layout MethodNotAllowed", "method not allowed handler should run after the kernel filters")
+
+	if allow := response.Header().Get("Allow"); allow != "GET, POST" {
+		t.Errorf("want Allow header %q got %q", "GET, POST", allow)
+	}
+}
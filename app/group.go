@@ -0,0 +1,48 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import "github.com/CloudyKit/framework/request"
+
+// Group returns a scoped kernel, routes and controllers added to the group are prefixed with prefix
+// and run the group filters after the filters of the parent, modifying the group Prefix, NamePrefix
+// or filters will not reflect in the parent kernel, groups can be nested.
+// ex: admin := kernel.Group("/admin", checkAuth)
+//
+//	admin.AddHandlerFunc("GET", "/dashboard", dashboard)
+//	admin.Group("/users").AddControllers(&UsersController{})
+func (kernel *Kernel) Group(prefix string, filters ...request.Handler) *Kernel {
+	group := kernel.Fork()
+	group.Prefix = kernel.Prefix + prefix
+	group.BindFilterHandlers(filters...)
+	return group
+}
+
+// NamedGroup works as Group, name is prepended to the route names and URLGen keys registered
+// in the group, ex: kernel.NamedGroup("admin.", "/admin").AddControllers(&Users{}) registers
+// the URLGen key "admin.app.Users.Index"
+func (kernel *Kernel) NamedGroup(name, prefix string, filters ...request.Handler) *Kernel {
+	group := kernel.Group(prefix, filters...)
+	group.NamePrefix = kernel.NamePrefix + name
+	return group
+}
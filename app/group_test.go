@@ -0,0 +1,101 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"github.com/CloudyKit/framework/request"
+	"github.com/CloudyKit/framework/tdutils"
+	"testing"
+)
+
+func writeFilter(txt string) request.HandlerFunc {
+	return func(c *request.Context) {
+		c.WriteString(txt)
+		c.Next()
+	}
+}
+
+type groupController struct {
+	*request.Context
+}
+
+func (c *groupController) Mx(mx *Mapper) {
+	mx.BindAction("GET", "/users/:id", "Show")
+}
+
+func (c *groupController) Show() {
+	c.WriteString("user " + c.GetURLParameter("id"))
+}
+
+func TestKernel_Group(t *testing.T) {
+	kernel := New()
+	kernel.BindFilterHandlers(writeFilter("global "))
+
+	admin := kernel.NamedGroup("admin.", "/admin", writeFilter("admin "))
+	admin.AddHandlerName("dashboard", "GET", "/dashboard", request.HandlerFunc(func(c *request.Context) {
+		c.WriteString(c.Name)
+	}))
+
+	api := admin.Group("/api", writeFilter("api "))
+	api.AddControllers(&groupController{})
+
+	kernel.AddHandlerFunc("GET", "/home", func(c *request.Context) {
+		c.WriteString("home")
+	})
+
+	if kernel.Prefix != "" || kernel.NamePrefix != "" || len(kernel.filters) != 1 {
+		t.Errorf("groups should not modify the parent kernel")
+	}
+
+	tester := tdutils.NewHTTPTester(t, kernel.Router)
+	tester.GetRequest("/admin/dashboard").ExpectOutput("global admin admin.dashboard", "group filters and name prefix should apply")
+	tester.GetRequest("/admin/api/users/10").ExpectOutput("global admin api user 10", "nested group filters should run in order")
+	tester.GetRequest("/home").ExpectOutput("global home", "group filters should not apply to the parent kernel")
+
+	if url := kernel.URLGen.URL("admin.dashboard"); url != "/admin/dashboard" {
+		t.Errorf("want url /admin/dashboard got %q", url)
+	}
+	showAction := "admin.app.groupController.Show"
+	if url := kernel.URLGen.URL(showAction, 10); url != "/admin/api/users/10" {
+		t.Errorf("want url /admin/api/users/10 got %q", url)
+	}
+}
+
+func TestKernel_BootstrapScope(t *testing.T) {
+	kernel := New()
+	kernel.Bootstrap(ComponentFunc(func(kernel *Kernel) {
+		kernel.Prefix = "/admin"
+		kernel.BindFilterHandlers(writeFilter("admin "))
+		kernel.AddHandlerFunc("GET", "/dashboard", func(c *request.Context) {
+			c.WriteString("dashboard")
+		})
+	}), ComponentFunc(func(kernel *Kernel) {
+		kernel.AddHandlerFunc("GET", "/home", func(c *request.Context) {
+			c.WriteString("home")
+		})
+	}))
+
+	tester := tdutils.NewHTTPTester(t, kernel.Router)
+	tester.GetRequest("/admin/dashboard").ExpectOutput("admin dashboard", "component prefix and filters should apply to its routes")
+	tester.GetRequest("/home").ExpectOutput("home", "component prefix and filters should not leak into the next component")
+}
@@ -0,0 +1,169 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"fmt"
+	"github.com/CloudyKit/framework/request"
+	"github.com/CloudyKit/router"
+	"net"
+	"strings"
+	"sync"
+)
+
+// hostRoute a host pattern and the router holding the routes of the host
+type hostRoute struct {
+	pattern string
+	labels  []string
+	router  *router.Router
+}
+
+type hostTable struct {
+	mx    sync.RWMutex
+	hosts []*hostRoute
+}
+
+// Host returns a scoped kernel, routes added to the host kernel only match requests for hosts matching
+// pattern and run the host filters after the filters of the parent, pattern labels in the format {name} are
+// host parameters, see request.Context.GetHostParameter. Requests for a host without a matching route fall
+// back to the routes without host.
+// ex: tenant := kernel.Host("{tenant}.example.com", loadTenant)
+//
+//	tenant.AddHandlerName("dashboard", "GET", "/", dashboard)
+func (kernel *Kernel) Host(pattern string, filters ...request.Handler) *Kernel {
+	host := kernel.Group("", filters...)
+	host.HostPattern = pattern
+	host.Router = kernel.hosts.router(pattern)
+	return host
+}
+
+// router returns the router of the host pattern, creating it case the pattern is new
+func (table *hostTable) router(pattern string) *router.Router {
+	table.mx.Lock()
+	defer table.mx.Unlock()
+	for _, host := range table.hosts {
+		if host.pattern == pattern {
+			return host.router
+		}
+	}
+	host := &hostRoute{pattern: pattern, labels: hostLabels(pattern), router: router.New()}
+	table.hosts = append(table.hosts, host)
+	return host.router
+}
+
+// match returns the host matching hostname and the matched parameters, static labels take precedence,
+// admin.example.com is preferred over {tenant}.example.com
+func (table *hostTable) match(hostname string) (matched *hostRoute, matchedParams request.HostParameters) {
+	table.mx.RLock()
+	defer table.mx.RUnlock()
+	if len(table.hosts) == 0 {
+		return nil, nil
+	}
+
+	labels := hostLabels(hostname)
+	for _, host := range table.hosts {
+		if params, ok := host.match(labels); ok && (matched == nil || len(params) < len(matchedParams)) {
+			matched, matchedParams = host, params
+		}
+	}
+	return
+}
+
+func (host *hostRoute) match(labels []string) (params request.HostParameters, ok bool) {
+	if len(labels) != len(host.labels) {
+		return nil, false
+	}
+	for i, label := range host.labels {
+		if isHostParameter(label) {
+			if params == nil {
+				params = request.HostParameters{}
+			}
+			params[label[1:len(label)-1]] = labels[i]
+		} else if label != labels[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// hostLabels splits the host without port in lower case labels
+func hostLabels(host string) []string {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	return strings.Split(strings.ToLower(strings.TrimSuffix(host, ".")), ".")
+}
+
+func isHostParameter(label string) bool {
+	return len(label) > 2 && label[0] == '{' && label[len(label)-1] == '}'
+}
+
+// expandHost replaces the parameters in the host pattern with values, the used values are deleted,
+// an error is returned for values that are not a DNS label, see isHostLabel
+func expandHost(name, pattern string, values map[string][]string) (string, error) {
+	labels := strings.Split(pattern, ".")
+	for i, label := range labels {
+		if !isHostParameter(label) {
+			continue
+		}
+		paramName := label[1 : len(label)-1]
+		paramValues := values[paramName]
+		if len(paramValues) == 0 {
+			return "", fmt.Errorf("app: route %q missing host parameter %q", name, paramName)
+		}
+		if !isHostLabel(paramValues[0]) {
+			return "", fmt.Errorf("app: route %q invalid host parameter %q: %q", name, paramName, paramValues[0])
+		}
+		delete(values, paramName)
+		labels[i] = paramValues[0]
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// isHostLabel reports whether label is a DNS label, 1 to 63 letters, digits or hyphens not starting
+// or ending with a hyphen
+func isHostLabel(label string) bool {
+	if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// hostFormat converts a host pattern into a format string, host parameters are replaced with %v
+func hostFormat(pattern string) string {
+	labels := strings.Split(pattern, ".")
+	for i, label := range labels {
+		if isHostParameter(label) {
+			labels[i] = "%v"
+		} else {
+			labels[i] = strings.ReplaceAll(label, "%", "%%")
+		}
+	}
+	return strings.Join(labels, ".")
+}
@@ -0,0 +1,88 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"github.com/CloudyKit/framework/request"
+	"github.com/CloudyKit/framework/tdutils"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestKernel_Host(t *testing.T) {
+	kernel := New()
+	kernel.URLs.BaseURL = "http://example.com"
+
+	kernel.AddHandlerName("home", "GET", "/", request.HandlerFunc(func(c *request.Context) {
+		c.WriteString("home")
+	}))
+
+	tenant := kernel.Host("{tenant}.example.com", writeFilter("tenant "))
+	tenant.AddHandlerName("tenant.home", "GET", "/", request.HandlerFunc(func(c *request.Context) {
+		c.WriteString(c.GetHostParameter("tenant"))
+	}))
+
+	admin := kernel.Host("admin.example.com")
+	admin.AddHandlerFunc("GET", "/", func(c *request.Context) {
+		c.WriteString("admin")
+	})
+
+	tester := tdutils.NewHTTPTester(t, kernel)
+	for host, want := range map[string]string{
+		"example.com":          "home",
+		"acme.example.com":     "tenant acme",
+		"ACME.example.com:443": "tenant acme",
+		"admin.example.com":    "admin",
+		"a.b.example.com":      "home",
+	} {
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.Host = host
+		tester.Request(r).ExpectOutput(want, "host %s: want %q", host, want)
+	}
+
+	if url, err := kernel.URLs.AbsoluteURL("tenant.home", map[string]string{"tenant": "acme", "tab": "1"}); err != nil || url != "http://acme.example.com/?tab=1" {
+		t.Errorf("want host url got %q %v", url, err)
+	}
+
+	if _, err := kernel.URLs.AbsoluteURL("tenant.home", nil); err == nil {
+		t.Error("missing host parameter should return an error")
+	}
+
+	for _, tenant := range []string{"x@evil.com/", "a b", "a.b", "-acme", "acme-", strings.Repeat("a", 64)} {
+		if url, err := kernel.URLs.AbsoluteURL("tenant.home", map[string]string{"tenant": tenant}); err == nil {
+			t.Errorf("host parameter %q is not a DNS label and should return an error, got %q", tenant, url)
+		}
+	}
+	if url, err := kernel.URLs.AbsoluteURL("tenant.home", map[string]string{"tenant": "acme-2"}); err != nil || url != "http://acme-2.example.com/" {
+		t.Errorf("want host url got %q %v", url, err)
+	}
+
+	tenantHome := "tenant.home"
+	if url := kernel.URLGen.URL(tenantHome, "acme"); url != "//acme.example.com/" {
+		t.Errorf("want scheme relative url //acme.example.com/ got %q", url)
+	}
+	if url := kernel.URLGen.URL("home"); url != "/" {
+		t.Errorf("want url / got %q", url)
+	}
+}
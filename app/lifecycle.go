@@ -0,0 +1,209 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Starter is implemented by components that need to start work before the kernel serves requests,
+// ex: open connections or run background workers, see Kernel.Start
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper is implemented by components that need to release resources when the kernel shuts down,
+// components are stopped in the reverse of the start order, see Kernel.Stop
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// Requirer is implemented by components depending on other components, Requires returns values of
+// the same type of the required components, ex: []app.Component{(*odm.Component)(nil)}
+// required components are bootstrapped, when passed in the same Kernel.Bootstrap call, and started before
+type Requirer interface {
+	Requires() []Component
+}
+
+// MissingRequirementError is returned by Kernel.Start when a required component was not bootstrapped
+type MissingRequirementError struct {
+	Component   Component
+	Requirement Component
+}
+
+func (err *MissingRequirementError) Error() string {
+	return fmt.Sprintf("app: component %T requires %T which was not bootstrapped", err.Component, err.Requirement)
+}
+
+// ComponentCycleError is returned by Kernel.Start when components require each other
+type ComponentCycleError struct {
+	Components []Component // Components the cycle, the first component is repeated at the end
+}
+
+func (err *ComponentCycleError) Error() string {
+	names := make([]string, len(err.Components))
+	for i, component := range err.Components {
+		names[i] = fmt.Sprintf("%T", component)
+	}
+	return "app: component requirements cycle " + strings.Join(names, " -> ")
+}
+
+// lifecycle components bootstrapped in the kernel, shared by the kernel forks
+type lifecycle struct {
+	mx         sync.Mutex
+	components []Component
+	started    []Component
+}
+
+func (lifecycle *lifecycle) add(components ...Component) {
+	lifecycle.mx.Lock()
+	lifecycle.components = append(lifecycle.components, components...)
+	lifecycle.mx.Unlock()
+}
+
+// Start resolves the dependency order of the bootstrapped components and starts them, case a component
+// fails to start the started components are stopped and the error is returned, missing requirements and
+// cycles are reported before any component is started, calling Start again after a successful start does nothing.
+// Start is called by Kernel.Serve before the server accepts requests
+func (kernel *Kernel) Start(ctx context.Context) error {
+	lifecycle := kernel.lifecycle
+	lifecycle.mx.Lock()
+	defer lifecycle.mx.Unlock()
+
+	if lifecycle.started != nil {
+		return nil
+	}
+
+	components, err := sortComponents(lifecycle.components, true)
+	if err != nil {
+		return err
+	}
+
+	started := make([]Component, 0, len(components))
+	for _, component := range components {
+		if starter, ok := component.(Starter); ok {
+			if err := starter.Start(ctx); err != nil {
+				err = fmt.Errorf("app: starting component %T: %w", component, err)
+				return errors.Join(err, stopComponents(ctx, started))
+			}
+		}
+		started = append(started, component)
+	}
+	lifecycle.started = started
+	return nil
+}
+
+// Stop stops the started components in the reverse of the start order, all the components are stopped
+// and the errors are joined. Stop is called by Kernel.Serve on shutdown
+func (kernel *Kernel) Stop(ctx context.Context) error {
+	lifecycle := kernel.lifecycle
+	lifecycle.mx.Lock()
+	defer lifecycle.mx.Unlock()
+
+	started := lifecycle.started
+	lifecycle.started = nil
+	return stopComponents(ctx, started)
+}
+
+func stopComponents(ctx context.Context, started []Component) error {
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		if stopper, ok := started[i].(Stopper); ok {
+			if err := stopper.Stop(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("app: stopping component %T: %w", started[i], err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sortComponents orders components placing the requirements before the components requiring them,
+// components without requirements between them keep their order, when strict is false missing
+// requirements and cycles are ignored.
+func sortComponents(components []Component, strict bool) ([]Component, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	byType := map[reflect.Type][]int{}
+	for i, component := range components {
+		typ := reflect.TypeOf(component)
+		byType[typ] = append(byType[typ], i)
+	}
+
+	var (
+		errs   []error
+		state  = make([]int, len(components))
+		sorted = make([]Component, 0, len(components))
+		path   []int
+		visit  func(i int)
+	)
+
+	visit = func(i int) {
+		switch state[i] {
+		case visited:
+			return
+		case visiting:
+			if strict {
+				cycle := []Component{}
+				for j := len(path) - 1; j >= 0; j-- {
+					cycle = append([]Component{components[path[j]]}, cycle...)
+					if path[j] == i {
+						break
+					}
+				}
+				errs = append(errs, &ComponentCycleError{Components: append(cycle, components[i])})
+			}
+			return
+		}
+
+		state[i] = visiting
+		path = append(path, i)
+		if requirer, ok := components[i].(Requirer); ok {
+			for _, requirement := range requirer.Requires() {
+				indexes, found := byType[reflect.TypeOf(requirement)]
+				if !found && strict {
+					errs = append(errs, &MissingRequirementError{Component: components[i], Requirement: requirement})
+				}
+				for _, j := range indexes {
+					visit(j)
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		sorted = append(sorted, components[i])
+	}
+
+	for i := range components {
+		visit(i)
+	}
+
+	return sorted, errors.Join(errs...)
+}
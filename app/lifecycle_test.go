@@ -0,0 +1,154 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type lifecycleLog []string
+
+type databaseComponent struct {
+	log *lifecycleLog
+	err error
+}
+
+func (component *databaseComponent) Bootstrap(kernel *Kernel) {
+	*component.log = append(*component.log, "bootstrap database")
+}
+
+func (component *databaseComponent) Start(ctx context.Context) error {
+	*component.log = append(*component.log, "start database")
+	return component.err
+}
+
+func (component *databaseComponent) Stop(ctx context.Context) error {
+	*component.log = append(*component.log, "stop database")
+	return nil
+}
+
+type sessionComponent struct {
+	log *lifecycleLog
+}
+
+func (component *sessionComponent) Bootstrap(kernel *Kernel) {
+	*component.log = append(*component.log, "bootstrap session")
+}
+
+func (component *sessionComponent) Requires() []Component {
+	return []Component{(*databaseComponent)(nil)}
+}
+
+func (component *sessionComponent) Start(ctx context.Context) error {
+	*component.log = append(*component.log, "start session")
+	return nil
+}
+
+func (component *sessionComponent) Stop(ctx context.Context) error {
+	*component.log = append(*component.log, "stop session")
+	return nil
+}
+
+type cacheComponent struct{}
+
+func (component *cacheComponent) Bootstrap(kernel *Kernel) {}
+
+func (component *cacheComponent) Requires() []Component {
+	return []Component{(*queueComponent)(nil)}
+}
+
+type queueComponent struct{}
+
+func (component *queueComponent) Bootstrap(kernel *Kernel) {}
+
+func (component *queueComponent) Requires() []Component {
+	return []Component{(*cacheComponent)(nil)}
+}
+
+func TestKernel_StartStop(t *testing.T) {
+	var log lifecycleLog
+	kernel := New()
+	kernel.Bootstrap(&sessionComponent{log: &log}, &databaseComponent{log: &log})
+
+	if err := kernel.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := kernel.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := lifecycleLog{
+		"bootstrap database", "bootstrap session",
+		"start database", "start session",
+		"stop session", "stop database",
+	}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("unexpected lifecycle order:\n got %v\nwant %v", log, expected)
+	}
+}
+
+func TestKernel_StartFailure(t *testing.T) {
+	var log lifecycleLog
+	kernel := New()
+	kernel.Bootstrap(&databaseComponent{log: &log})
+	kernel.Bootstrap(&sessionComponent{log: &log}, &databaseComponent{log: &log, err: errors.New("connection refused")})
+
+	if err := kernel.Start(context.Background()); err == nil {
+		t.Fatal("expected start error")
+	}
+
+	expected := lifecycleLog{
+		"bootstrap database", "bootstrap database", "bootstrap session",
+		"start database", "start database", "stop database",
+	}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("started components should be stopped:\n got %v\nwant %v", log, expected)
+	}
+}
+
+func TestKernel_StartRequirementErrors(t *testing.T) {
+	var log lifecycleLog
+	kernel := New()
+	kernel.Bootstrap(&sessionComponent{log: &log}, &cacheComponent{}, &queueComponent{})
+
+	err := kernel.Start(context.Background())
+
+	var missing *MissingRequirementError
+	if !errors.As(err, &missing) {
+		t.Errorf("expected a missing requirement error got %v", err)
+	}
+
+	var cycle *ComponentCycleError
+	if !errors.As(err, &cycle) {
+		t.Errorf("expected a cycle error got %v", err)
+	} else if len(cycle.Components) != 3 {
+		t.Errorf("unexpected cycle %v", cycle)
+	}
+
+	if len(log) != 1 {
+		t.Errorf("no component should start when requirements fail, got %v", log)
+	}
+}
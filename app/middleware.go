@@ -0,0 +1,47 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"github.com/CloudyKit/framework/request"
+	"github.com/CloudyKit/router"
+	"net/http"
+)
+
+// Middleware exports the kernel filters followed by filters as a standard net/http middleware,
+// the filters run with a registry forked from the kernel registry and the next handler is
+// invoked, with the ResponseWriter and *http.Request of the request context, when the last filter
+// calls Context.Next, the kernel filters are copied when Middleware is called.
+func (kernel *Kernel) Middleware(filters ...request.Handler) func(http.Handler) http.Handler {
+	filters = kernel.reSlice(filters...)
+	return func(next http.Handler) http.Handler {
+		handlers := append(filters[:len(filters):len(filters)], request.HandlerFunc(func(c *request.Context) {
+			next.ServeHTTP(c.Response, c.Request)
+		}))
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			c := newRequestContext()
+			defer requestRecover(c)
+			_ = request.DispatchNext(c, "", rw, r, router.Parameter{}, kernel.Registry.Fork(), handlers)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"github.com/CloudyKit/framework/request"
+	"github.com/CloudyKit/framework/tdutils"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type upperWriter struct {
+	http.ResponseWriter
+}
+
+func (w upperWriter) Write(p []byte) (int, error) {
+	return w.ResponseWriter.Write([]byte(strings.ToUpper(string(p))))
+}
+
+type middlewareKey struct{}
+
+func upperMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(upperWriter{rw}, r.WithContext(context.WithValue(r.Context(), middlewareKey{}, "value")))
+	})
+}
+
+func denyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("deny") != "" {
+			http.Error(rw, "denied", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
+
+func TestKernel_BindMiddlewares(t *testing.T) {
+	kernel := New()
+	kernel.BindMiddlewares(denyMiddleware, upperMiddleware)
+	kernel.AddHandlerFunc("GET", "/", func(c *request.Context) {
+		c.WriteString("hello " + c.Request.Context().Value(middlewareKey{}).(string))
+	})
+
+	tester := tdutils.NewHTTPTester(t, kernel)
+	tester.GetRequest("/").ExpectOutput("HELLO VALUE", "middleware writer and request should be propagated into the context")
+	tester.GetRequest("/?deny=1").ExpectStatus(http.StatusForbidden, "middleware should stop the request flow")
+}
+
+func TestKernel_Middleware(t *testing.T) {
+	kernel := New()
+	kernel.BindFilterHandlers(writeFilter("filter "))
+
+	handler := kernel.Middleware(request.WrapMiddleware(upperMiddleware))(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte("handler " + r.Context().Value(middlewareKey{}).(string)))
+	}))
+
+	tester := tdutils.NewHTTPTester(t, handler)
+	tester.GetRequest("/").ExpectOutput("filter HANDLER VALUE", "kernel filters should run before the next handler")
+}
@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"fmt"
+	"github.com/CloudyKit/framework/request"
+	"github.com/CloudyKit/router"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MountPathParameter name of the route parameter holding the path stripped of the mount prefix
+const MountPathParameter = "mountPath"
+
+// mountMethods methods routed to mounted handlers
+var mountMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodOptions, http.MethodConnect, http.MethodTrace,
+}
+
+// Mount routes every method and sub-path under prefix to handler, the prefix is stripped from the
+// request path before handler is called, a child kernel can be mounted as it implements http.Handler.
+// The kernel filters don't run for mounted handlers, see MountFiltered.
+// ex: kernel.Mount("/assets", http.FileServer(http.Dir("public")))
+func (kernel *Kernel) Mount(prefix string, handler http.Handler) {
+	prefix = strings.TrimRight(prefix, "/")
+	route := Route{Host: kernel.HostPattern, Handler: fmt.Sprintf("%T", handler)}
+
+	for _, path := range mountPaths(kernel.Prefix + prefix) {
+		route.Path = path
+		for _, method := range mountMethods {
+			route.Method = method
+			kernel.routes.add(route)
+			kernel.Router.AddRoute(method, path, func(rw http.ResponseWriter, r *http.Request, v router.Parameter) {
+				handler.ServeHTTP(rw, mountRequest(r, v.ByName(MountPathParameter)))
+			})
+		}
+	}
+}
+
+// MountFiltered works as Mount, but the request runs the kernel filters and the filters passed before handler,
+// the stripped path is available in the route parameter MountPathParameter
+func (kernel *Kernel) MountFiltered(prefix string, handler http.Handler, filters ...request.Handler) {
+	prefix = strings.TrimRight(prefix, "/")
+	mounted := &mountHandler{handler: handler}
+
+	for _, path := range mountPaths(prefix) {
+		kernel.AddHandlerContextName(kernel.Registry, "", strings.Join(mountMethods, "|"), path, mounted, filters...)
+	}
+}
+
+// mountHandler calls the mounted handler with the stripped request
+type mountHandler struct {
+	handler http.Handler
+}
+
+func (mounted *mountHandler) Handle(c *request.Context) {
+	mounted.handler.ServeHTTP(c.Response, mountRequest(c.Request, c.GetURLParameter(MountPathParameter)))
+}
+
+func (mounted *mountHandler) String() string {
+	return fmt.Sprintf("%T", mounted.handler)
+}
+
+// mountPaths returns the route paths matching prefix and every sub-path
+func mountPaths(prefix string) []string {
+	if prefix == "" {
+		return []string{"/*" + MountPathParameter}
+	}
+	return []string{prefix, prefix + "/*" + MountPathParameter}
+}
+
+// mountRequest returns a shallow copy of r with the path and the request uri replaced by the stripped path,
+// the query is kept
+func mountRequest(r *http.Request, path string) *http.Request {
+	mounted := new(http.Request)
+	*mounted = *r
+	mounted.URL = new(url.URL)
+	*mounted.URL = *r.URL
+	mounted.URL.Path = "/" + strings.TrimPrefix(path, "/")
+	mounted.URL.RawPath = ""
+	mounted.RequestURI = mounted.URL.RequestURI()
+	return mounted
+}
@@ -0,0 +1,57 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"github.com/CloudyKit/framework/request"
+	"github.com/CloudyKit/framework/tdutils"
+	"net/http"
+	"testing"
+)
+
+func TestKernel_Mount(t *testing.T) {
+	kernel := New()
+	kernel.BindFilterHandlers(writeFilter("filter "))
+
+	echoPath := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(r.Method + " " + r.URL.Path + " " + r.RequestURI))
+	})
+
+	child := New()
+	child.AddHandlerFunc("GET", "/users/:id", func(c *request.Context) {
+		c.WriteString("child user " + c.GetURLParameter("id"))
+	})
+
+	kernel.Mount("/raw", echoPath)
+	kernel.Mount("/child/", child)
+	kernel.MountFiltered("/filtered", echoPath)
+
+	tester := tdutils.NewHTTPTester(t, kernel)
+	tester.GetRequest("/raw/a/b?q=1").ExpectOutput("GET /a/b /a/b?q=1", "mounted handler should receive the stripped path")
+	tester.GetRequest("/raw").ExpectOutput("GET / /", "mount prefix should be routed")
+	tester.GetRequest("/child/users/5").ExpectOutput("child user 5", "child kernel should route the stripped path")
+	tester.GetRequest("/filtered/x").ExpectOutput("filter GET /x /x", "filtered mount should run the kernel filters")
+
+	r, _ := http.NewRequest("DELETE", "/raw/item", nil)
+	tester.Request(r).ExpectOutput("DELETE /item /item", "every method should be routed")
+}
@@ -0,0 +1,107 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	resourceType = reflect.TypeOf(Resource{})
+	actionType   = reflect.TypeOf(Action{})
+)
+
+// Resource embedded in a controller binds the controller routes by convention, the base path and the
+// name of the id parameter are declared in the tags path and param, additional routes are declared with
+// blank fields of type Action, ex:
+//
+//	type Users struct {
+//		app.Resource `path:"/users" param:"id"`
+//		_ app.Action `route:"GET /:id/avatar Avatar"`
+//	}
+//
+// a controller can implement its own Mx to bind filters and call Mapper.BindResource.
+type Resource struct{}
+
+// Action declares a route in a controller embedding Resource, the tag route has the format "METHOD path Action",
+// the path is relative to the resource path and multiple methods are separated by |
+type Action struct{}
+
+func (Resource) Mx(mx *Mapper) {
+	mx.BindResource()
+}
+
+// resourceConventions maps action names to the method and path relative to the resource path
+var resourceConventions = []struct {
+	action, method, path string
+}{
+	{"Index", "GET", ""},
+	{"Create", "POST", ""},
+	{"Show", "GET", "/:%s"},
+	{"Update", "PUT|PATCH", "/:%s"},
+	{"Destroy", "DELETE", "/:%s"},
+}
+
+// BindResource binds the controller methods named Index, Show, Create, Update and Destroy and the
+// routes declared with Action fields, see Resource
+func (mx *Mapper) BindResource() {
+	structTyp := mx.typ.Elem()
+	basePath, param := "", "id"
+
+	var declared []reflect.StructField
+	for i := 0; i < structTyp.NumField(); i++ {
+		field := structTyp.Field(i)
+		switch field.Type {
+		case resourceType:
+			basePath = strings.TrimRight(field.Tag.Get("path"), "/")
+			if tagParam := field.Tag.Get("param"); tagParam != "" {
+				param = tagParam
+			}
+		case actionType:
+			declared = append(declared, field)
+		}
+	}
+
+	for _, convention := range resourceConventions {
+		if _, found := mx.typ.MethodByName(convention.action); found {
+			path := basePath + convention.path
+			if strings.Contains(path, "%s") {
+				path = fmt.Sprintf(path, param)
+			}
+			if path == "" {
+				path = "/"
+			}
+			mx.BindAction(convention.method, path, convention.action)
+		}
+	}
+
+	for _, field := range declared {
+		parts := strings.Fields(field.Tag.Get("route"))
+		if len(parts) != 3 {
+			panic(fmt.Errorf("controller %s: invalid route declaration %q, expected \"METHOD path Action\"", mx.typ, field.Tag.Get("route")))
+		}
+		mx.BindAction(parts[0], basePath+parts[1], parts[2])
+	}
+}
@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"github.com/CloudyKit/framework/request"
+	"github.com/CloudyKit/framework/tdutils"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+type postsResource struct {
+	Resource `path:"/posts" param:"post"`
+	_        Action `route:"POST /:post/publish Publish"`
+}
+
+func (c *postsResource) Index() string              { return "index" }
+func (c *postsResource) Show(id int) string         { return "show " + strconv.Itoa(id) }
+func (c *postsResource) Destroy(id int) string      { return "destroy " + strconv.Itoa(id) }
+func (c *postsResource) Publish(id int) string      { return "publish " + strconv.Itoa(id) }
+func (c *postsResource) Helper(c2 *request.Context) {}
+
+type filteredResource struct {
+	Resource `path:"/filtered"`
+}
+
+func (c *filteredResource) Mx(mx *Mapper) {
+	mx.BindFilterHandlers(writeFilter("filter "))
+	mx.BindResource()
+}
+
+func (c *filteredResource) Index() string { return "index" }
+
+func TestMapper_BindResource(t *testing.T) {
+	kernel := New()
+	kernel.AddControllers(&postsResource{}, &filteredResource{})
+
+	tester := tdutils.NewHTTPTester(t, kernel)
+	tester.GetRequest("/posts").ExpectOutput("index", "Index should be bound to GET /posts")
+	tester.GetRequest("/posts/5").ExpectOutput("show 5", "Show should be bound to GET /posts/:post")
+	tester.GetRequest("/filtered").ExpectOutput("filter index", "mapper filters should be honored")
+
+	r, _ := http.NewRequest("DELETE", "/posts/5", nil)
+	tester.Request(r).ExpectOutput("destroy 5", "Destroy should be bound to DELETE /posts/:post")
+
+	r, _ = http.NewRequest("POST", "/posts/7/publish", nil)
+	tester.Request(r).ExpectOutput("publish 7", "declared routes should be bound")
+
+	for _, route := range kernel.Routes() {
+		if route.Action == "Create" {
+			t.Errorf("Create is not declared and should not be bound")
+		}
+	}
+
+	showAction := "app.postsResource.Show"
+	if url := kernel.URLGen.URL(showAction, 5); url != "/posts/5" {
+		t.Errorf("want url /posts/5 got %q", url)
+	}
+}
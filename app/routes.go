@@ -0,0 +1,212 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/CloudyKit/framework/request"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// Route describes a route registered in the kernel
+type Route struct {
+	Host       string   `json:"host,omitempty"`
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Name       string   `json:"name,omitempty"`
+	Controller string   `json:"controller,omitempty"`
+	Action     string   `json:"action,omitempty"`
+	Handler    string   `json:"handler,omitempty"`
+	Filters    []string `json:"filters,omitempty"`
+}
+
+// Routes the list of routes in registration order, see Kernel.Routes
+type Routes []Route
+
+// RouteConflict two routes with the same method matching the same requests, routes with the same path
+// shape replace each other in the router, overlapping routes depend on the router precedence
+type RouteConflict struct {
+	First, Last Route
+	Duplicate   bool // Duplicate paths are identical, otherwise only the parameter names differ
+	Overlap     bool // Overlap a static segment of a path matches a parameter of the other, ex: /users/new and /users/:id
+}
+
+func (conflict RouteConflict) String() string {
+	kind := "shadows"
+	if conflict.Duplicate {
+		kind = "duplicates"
+	} else if conflict.Overlap {
+		kind = "overlaps"
+	}
+	return fmt.Sprintf("%s %s (%s) %s %s (%s)", conflict.Last.Method, conflict.Last.Path, conflict.Last.description(),
+		kind, conflict.First.Path, conflict.First.description())
+}
+
+type routeTable struct {
+	mx      sync.RWMutex
+	routes  Routes
+	methods map[string][]string // methods the sorted methods of the routes by host pattern, see Kernel.allowedMethods
+}
+
+func (table *routeTable) add(route Route) {
+	table.mx.Lock()
+	defer table.mx.Unlock()
+	table.routes = append(table.routes, route)
+
+	// the slices are replaced, not modified, as they are read without the lock by Kernel.allowedMethods
+	methods := table.methods[route.Host]
+	if i := sort.SearchStrings(methods, route.Method); i == len(methods) || methods[i] != route.Method {
+		if table.methods == nil {
+			table.methods = map[string][]string{}
+		}
+		added := make([]string, 0, len(methods)+1)
+		added = append(append(append(added, methods[:i]...), route.Method), methods[i:]...)
+		table.methods[route.Host] = added
+	}
+}
+
+// hostMethods returns the methods of the routes without host and of the routes of hostPattern
+func (table *routeTable) hostMethods(hostPattern string) (common, host []string) {
+	table.mx.RLock()
+	defer table.mx.RUnlock()
+	if hostPattern != "" {
+		host = table.methods[hostPattern]
+	}
+	return table.methods[""], host
+}
+
+// Routes returns a copy of the routes registered in the kernel and its groups
+func (kernel *Kernel) Routes() Routes {
+	kernel.routes.mx.RLock()
+	defer kernel.routes.mx.RUnlock()
+	return append(Routes(nil), kernel.routes.routes...)
+}
+
+// CheckRoutes returns an error describing the duplicate or shadowed routes
+func (kernel *Kernel) CheckRoutes() error {
+	conflicts := kernel.Routes().Conflicts()
+	if len(conflicts) == 0 {
+		return nil
+	}
+	lines := make([]string, len(conflicts))
+	for i, conflict := range conflicts {
+		lines[i] = conflict.String()
+	}
+	return fmt.Errorf("app: conflicting routes:\n\t%s", strings.Join(lines, "\n\t"))
+}
+
+// Conflicts returns the routes with the same method and path shape, and the routes with the same method
+// and overlapping paths, each route is reported with the last route of the conflicting shapes
+func (routes Routes) Conflicts() (conflicts []RouteConflict) {
+	seen := map[string]int{}
+	for i, route := range routes {
+		shape := routeShape(route.Path)
+		key := route.Host + " " + route.Method + " " + shape
+		if j, found := seen[key]; found {
+			conflicts = append(conflicts, RouteConflict{First: routes[j], Last: route, Duplicate: routes[j].Path == route.Path})
+		}
+		seen[key] = i
+
+		reported := map[string]bool{}
+		for j := i - 1; j >= 0; j-- {
+			other := routes[j]
+			otherShape := routeShape(other.Path)
+			if other.Host != route.Host || other.Method != route.Method || otherShape == shape || reported[otherShape] {
+				continue
+			}
+			reported[otherShape] = true
+			if pathsOverlap(route.Path, other.Path) {
+				conflicts = append(conflicts, RouteConflict{First: other, Last: route, Overlap: true})
+			}
+		}
+	}
+	return
+}
+
+// pathsOverlap reports if a request path can match both paths, a parameter matches any non empty segment
+// and a wildcard the remaining segments
+func pathsOverlap(a, b string) bool {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, y := as[i], bs[i]
+		switch {
+		case strings.HasPrefix(x, "*") || strings.HasPrefix(y, "*"):
+			return true
+		case x == y, strings.HasPrefix(x, ":") && y != "", strings.HasPrefix(y, ":") && x != "":
+			continue
+		}
+		return false
+	}
+	return len(as) == len(bs)
+}
+
+// String returns the routes as an aligned table
+func (routes Routes) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tHOST\tPATH\tNAME\tHANDLER\tFILTERS")
+	for _, route := range routes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", route.Method, route.Host, route.Path, route.Name, route.description(), strings.Join(route.Filters, ", "))
+	}
+	w.Flush()
+	return buf.String()
+}
+
+// JSON returns the routes as indented json
+func (routes Routes) JSON() ([]byte, error) {
+	return json.MarshalIndent(routes, "", "  ")
+}
+
+func (route Route) description() string {
+	if route.Controller != "" {
+		return route.Controller + "." + route.Action
+	}
+	return route.Handler
+}
+
+// routeShape replaces the parameter names in path, /users/:id and /users/:name have the same shape
+func routeShape(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment != "" && (segment[0] == ':' || segment[0] == '*') {
+			segments[i] = segment[:1]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func handlerNames(handlers []request.Handler) []string {
+	if len(handlers) == 0 {
+		return nil
+	}
+	names := make([]string, len(handlers))
+	for i, handler := range handlers {
+		names[i] = request.HandlerName(handler)
+	}
+	return names
+}
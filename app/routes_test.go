@@ -0,0 +1,109 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"encoding/json"
+	"github.com/CloudyKit/framework/request"
+	"strings"
+	"testing"
+)
+
+func TestKernel_Routes(t *testing.T) {
+	kernel := New()
+	kernel.BindFilterHandlers(writeFilter("global "))
+
+	kernel.AddHandlerName("home", "GET|HEAD", "/", request.HandlerFunc(func(c *request.Context) {}))
+	kernel.Group("/api").AddControllers(&groupController{})
+
+	routes := kernel.Routes()
+	if len(routes) != 3 {
+		t.Fatalf("want 3 routes got %d", len(routes))
+	}
+
+	if routes[0].Method != "GET" || routes[1].Method != "HEAD" || routes[0].Name != "home" {
+		t.Errorf("unexpected route %+v", routes[0])
+	}
+
+	if routes[2].Path != "/api/users/:id" || routes[2].Controller != "app.groupController" || routes[2].Action != "Show" {
+		t.Errorf("unexpected controller route %+v", routes[2])
+	}
+
+	if len(routes[2].Filters) != 1 || !strings.Contains(routes[2].Filters[0], "writeFilter") {
+		t.Errorf("unexpected filters %v", routes[2].Filters)
+	}
+
+	if !strings.Contains(routes.String(), "/api/users/:id") {
+		t.Errorf("route table dump is missing routes:\n%s", routes)
+	}
+
+	data, err := routes.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Routes
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded) != 3 {
+		t.Errorf("unexpected json dump %s", data)
+	}
+
+	if err := kernel.CheckRoutes(); err != nil {
+		t.Errorf("unexpected conflicts %v", err)
+	}
+
+	kernel.AddHandlerFunc("GET", "/api/users/:name", func(c *request.Context) {})
+	kernel.AddHandlerFunc("HEAD", "/", func(c *request.Context) {})
+
+	conflicts := kernel.Routes().Conflicts()
+	if len(conflicts) != 2 || conflicts[0].Duplicate || !conflicts[1].Duplicate {
+		t.Errorf("unexpected conflicts %v", conflicts)
+	}
+
+	kernel.AddHandlerFunc("GET", "/api/users/new", func(c *request.Context) {})
+	kernel.AddHandlerFunc("GET", "/api/users/new/posts", func(c *request.Context) {})
+	kernel.AddHandlerFunc("POST", "/api/users/new", func(c *request.Context) {})
+	conflicts = kernel.Routes().Conflicts()
+	if len(conflicts) != 3 || !conflicts[2].Overlap || conflicts[2].First.Path != "/api/users/:name" || conflicts[2].Last.Path != "/api/users/new" {
+		t.Errorf("static segments matching a parameter should overlap, got %v", conflicts)
+	}
+	if !strings.Contains(conflicts[2].String(), "overlaps") {
+		t.Errorf("unexpected conflict description %s", conflicts[2])
+	}
+}
+
+func TestPathsOverlap(t *testing.T) {
+	for _, test := range []struct {
+		a, b    string
+		overlap bool
+	}{
+		{"/users/new", "/users/:id", true},
+		{"/users/:id/posts", "/users/new/:post", true},
+		{"/files/*path", "/files/a/b", true},
+		{"/files", "/files/*path", false},
+		{"/users/new", "/users/:id/edit", false},
+		{"/users/new", "/posts/:id", false},
+	} {
+		if overlap := pathsOverlap(test.a, test.b); overlap != test.overlap {
+			t.Errorf("%s %s: expected overlap %v", test.a, test.b, test.overlap)
+		}
+	}
+}
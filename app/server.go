@@ -0,0 +1,271 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultShutdownTimeout is the time given to in-flight requests to finish when
+// Kernel.ShutdownTimeout is not set
+const DefaultShutdownTimeout = 30 * time.Second
+
+// ServerOptions holds the http.Server settings and the listeners used to serve the kernel
+type ServerOptions struct {
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	ErrorLog          *log.Logger
+	TLSConfig         *tls.Config
+
+	Listeners []Listener // Listeners the server will accept connections on, all at once
+}
+
+// Listener describes where the server accepts connections, see ListenTCP, ListenTLS, ListenUnix,
+// ListenFD and ListenOn
+type Listener struct {
+	Network string // Network "tcp", "tcp4", "tcp6" or "unix", defaults to "tcp"
+	Address string // Address host:port or socket path
+
+	TLS      bool   // TLS serves https in this listener, see ServerOptions.TLSConfig
+	CertFile string // CertFile optional when ServerOptions.TLSConfig carries the certificates
+	KeyFile  string
+
+	File     *os.File     // File an inherited socket file descriptor
+	Listener net.Listener // Listener a pre-opened listener
+}
+
+// ListenTCP listens on a tcp address
+func ListenTCP(address string) Listener {
+	return Listener{Network: "tcp", Address: address}
+}
+
+// ListenTLS listens on a tcp address serving https with the certificate and key files
+func ListenTLS(address, certfile, keyfile string) Listener {
+	return Listener{Network: "tcp", Address: address, TLS: true, CertFile: certfile, KeyFile: keyfile}
+}
+
+// ListenUnix listens on a unix socket, a socket file in path not accepting connections is removed
+func ListenUnix(path string) Listener {
+	return Listener{Network: "unix", Address: path}
+}
+
+// ListenFD listens on a socket file descriptor inherited from the parent process
+func ListenFD(fd uintptr, name string) Listener {
+	return Listener{Address: name, File: os.NewFile(fd, name)}
+}
+
+// ListenOn serves on an already opened listener
+func ListenOn(listener net.Listener) Listener {
+	return Listener{Network: listener.Addr().Network(), Address: listener.Addr().String(), Listener: listener}
+}
+
+func (listener Listener) listen() (net.Listener, error) {
+	if listener.Listener != nil {
+		return listener.Listener, nil
+	}
+
+	if listener.File != nil {
+		defer listener.File.Close()
+		return net.FileListener(listener.File)
+	}
+
+	network := listener.Network
+	if network == "" {
+		network = "tcp"
+	}
+
+	if network == "unix" {
+		if err := removeStaleSocket(listener.Address); err != nil {
+			return nil, err
+		}
+	}
+
+	return net.Listen(network, listener.Address)
+}
+
+// removeStaleSocket removes the socket file in path left by a process that is no longer serving it,
+// an address in use error is returned when the socket accepts connections, other files are kept
+func removeStaleSocket(path string) error {
+	stat, err := os.Lstat(path)
+	if err != nil || stat.Mode()&os.ModeSocket == 0 {
+		return nil
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return &net.OpError{Op: "listen", Net: "unix", Addr: &net.UnixAddr{Name: path, Net: "unix"}, Err: syscall.EADDRINUSE}
+	}
+	return os.Remove(path)
+}
+
+// WithListeners returns a copy of the options serving on listeners
+func (options ServerOptions) WithListeners(listeners ...Listener) *ServerOptions {
+	options.Listeners = listeners
+	return &options
+}
+
+func (options *ServerOptions) logger() *log.Logger {
+	if options.ErrorLog != nil {
+		return options.ErrorLog
+	}
+	return log.Default()
+}
+
+func (options *ServerOptions) newServer(handler http.Handler) *http.Server {
+	server := &http.Server{
+		Handler:           handler,
+		ReadTimeout:       options.ReadTimeout,
+		ReadHeaderTimeout: options.ReadHeaderTimeout,
+		WriteTimeout:      options.WriteTimeout,
+		IdleTimeout:       options.IdleTimeout,
+		MaxHeaderBytes:    options.MaxHeaderBytes,
+		ErrorLog:          options.ErrorLog,
+		TLSConfig:         options.TLSConfig,
+	}
+	if len(options.Listeners) > 0 {
+		server.Addr = options.Listeners[0].Address
+	}
+	return server
+}
+
+// signalContext returns a context that is canceled when the process receives SIGINT or SIGTERM
+func signalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// Serve runs the server with the specified host until ctx is done, the server uses the settings
+// in Kernel.Server.
+// Calling this func will emit a "hub.run" event in the app, see Kernel.shutdown
+func (kernel *Kernel) Serve(ctx context.Context, host string) error {
+	e := &RunServerEvent{Host: host, Options: kernel.Server.WithListeners(ListenTCP(host))}
+	kernel.Dispatch("hub.run", e)
+	return kernel.serve(ctx, e.Options)
+}
+
+// ServeTLS runs the server in tls mode until ctx is done, the server uses the settings
+// in Kernel.Server.
+// Calling this func will emit a "hub.run.tls" event in the app, see Kernel.shutdown
+func (kernel *Kernel) ServeTLS(ctx context.Context, host, certfile, keyfile string) error {
+	e := &RunServerEventTLS{Host: host, CertFile: certfile, KeyFile: keyfile}
+	e.Options = kernel.Server.WithListeners(ListenTLS(kernel.host(host), certfile, keyfile))
+	kernel.Dispatch("hub.run.tls", e)
+	return kernel.serve(ctx, e.Options)
+}
+
+// ServeWith runs the server on all listeners in options until ctx is done
+// Calling this func will emit a "hub.run" event in the app, see Kernel.shutdown
+func (kernel *Kernel) ServeWith(ctx context.Context, options *ServerOptions) error {
+	e := &RunServerEvent{Options: options}
+	if len(options.Listeners) > 0 {
+		e.Host = options.Listeners[0].Address
+	}
+	kernel.Dispatch("hub.run", e)
+	return kernel.serve(ctx, e.Options)
+}
+
+// serve opens the listeners and serves each one in a goroutine, it waits for a listener to fail
+// or for ctx to be done, in both cases the server is shutdown gracefully, see Kernel.shutdown
+func (kernel *Kernel) serve(ctx context.Context, options *ServerOptions) error {
+	if len(options.Listeners) == 0 {
+		return errors.New("app: no listeners to serve")
+	}
+
+	listeners := make([]net.Listener, 0, len(options.Listeners))
+	for _, listener := range options.Listeners {
+		l, err := listener.listen()
+		if err != nil {
+			for _, l := range listeners {
+				_ = l.Close()
+			}
+			return err
+		}
+		listeners = append(listeners, l)
+	}
+
+	if err := kernel.Start(ctx); err != nil {
+		for _, l := range listeners {
+			_ = l.Close()
+		}
+		return err
+	}
+
+	server := options.newServer(kernel)
+
+	if err := kernel.CheckRoutes(); err != nil {
+		options.logger().Println(err)
+	}
+
+	serverErr := make(chan error, len(listeners))
+	for i, l := range listeners {
+		listener := options.Listeners[i]
+		go func(l net.Listener) {
+			if listener.TLS {
+				serverErr <- server.ServeTLS(l, listener.CertFile, listener.KeyFile)
+			} else {
+				serverErr <- server.Serve(l)
+			}
+		}(l)
+	}
+
+	select {
+	case err := <-serverErr:
+		// a listener failed, the server is shutdown closing the remaining listeners
+		return errors.Join(err, kernel.shutdown(server))
+	case <-ctx.Done():
+		return kernel.shutdown(server)
+	}
+}
+
+// shutdown stops the server, waits the in-flight requests to finish within Kernel.ShutdownTimeout,
+// stops the started components, emits a "hub.shutdown" event so components can release their resources
+// and finally disposes the kernel
+func (kernel *Kernel) shutdown(server *http.Server) error {
+	timeout := kernel.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := server.Shutdown(ctx)
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+	err = errors.Join(err, kernel.Stop(ctx))
+
+	kernel.Dispatch("hub.shutdown", &ShutdownEvent{Server: server, Context: ctx})
+	kernel.Dispose()
+	return err
+}
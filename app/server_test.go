@@ -0,0 +1,170 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"github.com/CloudyKit/framework/request"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestKernel_ServeShutdown(t *testing.T) {
+	kernel := New()
+
+	shutdown := false
+	kernel.Subscribe("hub.shutdown", func(e *ShutdownEvent) {
+		shutdown = true
+		if _, hasDeadline := e.Context.Deadline(); !hasDeadline {
+			t.Error("shutdown context should carry a deadline")
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	if err := kernel.Serve(ctx, "127.0.0.1:0"); err != nil {
+		t.Fatalf("unexpected error on graceful shutdown: %v", err)
+	}
+
+	if !shutdown {
+		t.Error("hub.shutdown event was not dispatched")
+	}
+}
+
+func TestKernel_ServeListenerError(t *testing.T) {
+	kernel := New()
+
+	shutdown := false
+	kernel.Subscribe("hub.shutdown", func(e *ShutdownEvent) {
+		shutdown = true
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener.Close()
+
+	if err := kernel.ServeWith(context.Background(), &ServerOptions{Listeners: []Listener{ListenOn(listener)}}); err == nil {
+		t.Fatal("expected the error of the closed listener")
+	}
+	if !shutdown {
+		t.Error("hub.shutdown event was not dispatched after the listener error")
+	}
+}
+
+func TestKernel_ServeWithListeners(t *testing.T) {
+	kernel := New()
+	kernel.AddHandlerFunc("GET", "/", func(c *request.Context) {
+		c.WriteString("ok")
+	})
+
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	socketPath := filepath.Join(t.TempDir(), "kernel.sock")
+
+	kernel.Subscribe("hub.run", func(e *RunServerEvent) {
+		e.Options.ReadHeaderTimeout = time.Second
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- kernel.ServeWith(ctx, &ServerOptions{
+			Listeners: []Listener{ListenOn(tcpListener), ListenUnix(socketPath)},
+		})
+	}()
+
+	unixClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return new(net.Dialer).DialContext(ctx, "unix", socketPath)
+		},
+	}}
+
+	for _, test := range []struct {
+		client *http.Client
+		url    string
+	}{
+		{http.DefaultClient, "http://" + tcpListener.Addr().String() + "/"},
+		{unixClient, "http://unix/"},
+	} {
+		var response *http.Response
+		for retries := 0; retries < 50; retries++ {
+			if response, err = test.client.Get(test.url); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if err != nil {
+			t.Fatalf("request to %s failed: %v", test.url, err)
+		}
+		response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			t.Errorf("request to %s: want status 200 got %d", test.url, response.StatusCode)
+		}
+	}
+
+	cancel()
+	if err := <-served; err != nil {
+		t.Fatalf("unexpected error on graceful shutdown: %v", err)
+	}
+}
+
+func TestListenUnix(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "kernel.sock")
+	served, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ListenUnix(socketPath).listen(); !errors.Is(err, syscall.EADDRINUSE) {
+		t.Fatalf("a socket accepting connections should not be removed, got %v", err)
+	}
+
+	// closing a unix listener removes its file, a stale socket is left by a listener not unlinking it
+	served.(*net.UnixListener).SetUnlinkOnClose(false)
+	served.Close()
+	listener, err := ListenUnix(socketPath).listen()
+	if err != nil {
+		t.Fatalf("a stale socket should be replaced, got %v", err)
+	}
+	listener.Close()
+
+	filePath := filepath.Join(t.TempDir(), "kernel.txt")
+	os.WriteFile(filePath, []byte("data"), 0644)
+	if _, err := ListenUnix(filePath).listen(); err == nil {
+		t.Error("a file that is not a socket should not be removed")
+	}
+	if _, err := os.Stat(filePath); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,227 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"github.com/CloudyKit/framework/container"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+var URLBuilderType = reflect.TypeOf((*URLBuilder)(nil))
+
+// GetURLBuilder gets the URLBuilder from the Registry context
+func GetURLBuilder(c *container.Registry) *URLBuilder {
+	builder, _ := c.LoadType(URLBuilderType).(*URLBuilder)
+	return builder
+}
+
+// URLBuilder builds urls from route names and named parameters, the routes are registered by
+// Kernel.AddHandlerName and Mapper.BindAction with the same keys used by the URLGen
+type URLBuilder struct {
+	BaseURL string // BaseURL scheme and host used by AbsoluteURL, ex: https://example.com
+	routes  map[string]urlRoute
+}
+
+type urlRoute struct {
+	pattern string
+	host    string
+}
+
+// NewURLBuilder creates an URLBuilder, baseURL is used to generate absolute urls
+func NewURLBuilder(baseURL string) *URLBuilder {
+	return &URLBuilder{BaseURL: baseURL, routes: make(map[string]urlRoute)}
+}
+
+// Add registers the route pattern with name
+func (builder *URLBuilder) Add(name, pattern string) {
+	builder.AddHost(name, "", pattern)
+}
+
+// AddHost registers the route pattern with name, the route is served in the hosts matching hostPattern,
+// see Kernel.Host
+func (builder *URLBuilder) AddHost(name, hostPattern, pattern string) {
+	builder.routes[name] = urlRoute{pattern: pattern, host: hostPattern}
+}
+
+// Pattern returns the route pattern registered with name
+func (builder *URLBuilder) Pattern(name string) (pattern string, found bool) {
+	route, found := builder.routes[name]
+	return route.pattern, found
+}
+
+// URL generates the path of the route name, params can be a map with string keys, url.Values or a struct,
+// struct fields are named by the tag url or by the field name, ex: `url:"id"` or `url:"page,omitempty"`.
+// Route parameters are escaped and the remaining values are appended as query string, an error is returned
+// case the route is not found or a route parameter is missing.
+// ex: builder.URL("app.Users.Show", map[string]interface{}{"id": 10, "tab": "posts"}) => /users/10?tab=posts
+func (builder *URLBuilder) URL(name string, params interface{}) (string, error) {
+	_, path, err := builder.build(name, params)
+	return path, err
+}
+
+// AbsoluteURL works as URL, but the generated path is prefixed with BaseURL, routes registered with
+// a host pattern target their host, the host parameters are taken from params and the scheme from BaseURL
+// ex: builder.AbsoluteURL("tenant.dashboard", map[string]string{"tenant": "acme"}) => https://acme.example.com/
+func (builder *URLBuilder) AbsoluteURL(name string, params interface{}) (string, error) {
+	host, path, err := builder.build(name, params)
+	if err != nil {
+		return "", err
+	}
+
+	if host != "" {
+		scheme := "https"
+		if baseURL, err := url.Parse(builder.BaseURL); err == nil && baseURL.Scheme != "" {
+			scheme = baseURL.Scheme
+		}
+		return scheme + "://" + host + path, nil
+	}
+
+	if builder.BaseURL == "" {
+		return "", errors.New("app: URLBuilder.BaseURL is not configured")
+	}
+	return strings.TrimRight(builder.BaseURL, "/") + path, nil
+}
+
+// build returns the expanded host, when the route has a host pattern, and the path of the route
+func (builder *URLBuilder) build(name string, params interface{}) (host, path string, err error) {
+	route, found := builder.routes[name]
+	if !found {
+		return "", "", fmt.Errorf("app: route %q is not registered", name)
+	}
+
+	values, err := urlValues(params)
+	if err != nil {
+		return "", "", err
+	}
+
+	if route.host != "" {
+		if host, err = expandHost(name, route.host, values); err != nil {
+			return "", "", err
+		}
+	}
+
+	segments := strings.Split(route.pattern, "/")
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+
+		paramName := segment[1:]
+		paramValues, found := values[paramName]
+		if !found || len(paramValues) == 0 {
+			return "", "", fmt.Errorf("app: route %q missing parameter %q", name, paramName)
+		}
+		delete(values, paramName)
+
+		if segment[0] == ':' {
+			segments[i] = url.PathEscape(paramValues[0])
+			continue
+		}
+
+		parts := strings.Split(strings.TrimPrefix(paramValues[0], "/"), "/")
+		for j := range parts {
+			parts[j] = url.PathEscape(parts[j])
+		}
+		segments[i] = strings.Join(parts, "/")
+	}
+
+	path = strings.Join(segments, "/")
+	if len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+	return host, path, nil
+}
+
+// urlValues converts params into url.Values
+func urlValues(params interface{}) (url.Values, error) {
+	values := url.Values{}
+	if params == nil {
+		return values, nil
+	}
+
+	if params, ok := params.(url.Values); ok {
+		for key, value := range params {
+			values[key] = append([]string(nil), value...)
+		}
+		return values, nil
+	}
+
+	paramsValue := reflect.Indirect(reflect.ValueOf(params))
+	switch paramsValue.Kind() {
+	case reflect.Map:
+		if paramsValue.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("app: url parameters of type %s should have string keys", paramsValue.Type())
+		}
+		iter := paramsValue.MapRange()
+		for iter.Next() {
+			addURLValue(values, iter.Key().String(), iter.Value())
+		}
+	case reflect.Struct:
+		structType := paramsValue.Type()
+		for i := 0; i < structType.NumField(); i++ {
+			field := structType.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			name, opts, _ := strings.Cut(field.Tag.Get("url"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			fieldValue := paramsValue.Field(i)
+			if opts == "omitempty" && fieldValue.IsZero() {
+				continue
+			}
+			addURLValue(values, name, fieldValue)
+		}
+	default:
+		return nil, fmt.Errorf("app: unsupported url parameters of type %T", params)
+	}
+	return values, nil
+}
+
+func addURLValue(values url.Values, name string, value reflect.Value) {
+	for value.Kind() == reflect.Interface || value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	if (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) && value.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < value.Len(); i++ {
+			values.Add(name, fmt.Sprint(value.Index(i).Interface()))
+		}
+		return
+	}
+
+	values.Add(name, fmt.Sprint(value.Interface()))
+}
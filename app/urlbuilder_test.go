@@ -0,0 +1,72 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package app
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestURLBuilder_URL(t *testing.T) {
+	builder := NewURLBuilder("https://example.com/")
+	builder.Add("posts", "/users/:id/posts/:post")
+	builder.Add("static", "/static/*file")
+
+	type postParams struct {
+		Post int    `url:"post"`
+		ID   string `url:"id"`
+		Page int    `url:"page,omitempty"`
+		Tab  string `url:"tab"`
+	}
+
+	for _, test := range []struct {
+		name   string
+		params interface{}
+		want   string
+	}{
+		{"posts", map[string]interface{}{"id": "john doe", "post": 5}, "/users/john%20doe/posts/5"},
+		{"posts", map[string]interface{}{"id": 1, "post": 2, "sort": []string{"asc", "date"}}, "/users/1/posts/2?sort=asc&sort=date"},
+		{"posts", url.Values{"id": {"1"}, "post": {"2"}, "q": {"a&b"}}, "/users/1/posts/2?q=a%26b"},
+		{"posts", &postParams{Post: 3, ID: "7", Tab: "all"}, "/users/7/posts/3?tab=all"},
+		{"static", map[string]string{"file": "css/app main.css"}, "/static/css/app%20main.css"},
+	} {
+		got, err := builder.URL(test.name, test.params)
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		} else if got != test.want {
+			t.Errorf("want %q got %q", test.want, got)
+		}
+	}
+
+	if _, err := builder.URL("posts", map[string]int{"id": 1}); err == nil {
+		t.Error("missing parameter should return an error")
+	}
+
+	if _, err := builder.URL("unknown", nil); err == nil {
+		t.Error("unknown route should return an error")
+	}
+
+	if got, _ := builder.AbsoluteURL("posts", map[string]int{"id": 1, "post": 2}); got != "https://example.com/users/1/posts/2" {
+		t.Errorf("want absolute url got %q", got)
+	}
+}
@@ -25,8 +25,34 @@ package app
 import (
 	"fmt"
 	"github.com/CloudyKit/framework/common"
+	"regexp"
 )
 
+var acRegex = regexp.MustCompile("/[:*][^/]+")
+
+// urlFormat converts a route path into a format string, route parameters are replaced with %v
+func urlFormat(path string) string {
+	return acRegex.ReplaceAllStringFunc(path, func(st string) string {
+		if st[1] == '*' {
+			return "%v"
+		}
+		return "/%v"
+	})
+}
+
+// addURL registers the route path with name in the URLGen and in the URLBuilder, routes of a host are
+// registered in the URLGen as scheme relative urls taking the host parameters first,
+// ex: kernel.Host("{tenant}.example.com").AddHandlerName("dashboard", "GET", "/users/:id", ...) is generated
+// by URLGen.URL("dashboard", "acme", 10) => //acme.example.com/users/10
+func (kernel *Kernel) addURL(name, path string) {
+	format := urlFormat(path)
+	if kernel.HostPattern != "" {
+		format = "//" + hostFormat(kernel.HostPattern) + format
+	}
+	kernel.URLGen[name] = format
+	kernel.URLs.AddHost(name, kernel.HostPattern, path)
+}
+
 type MapURLGen map[string]string
 type ControllerURLGen struct {
 	urlGen MapURLGen
@@ -0,0 +1,283 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/CloudyKit/framework/container"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// ErrRequired is reported for required keys not set by any source
+var ErrRequired = errors.New("required value not set")
+
+// Error describes a missing or invalid configuration key
+type Error struct {
+	Key    string // Key configuration key, ex: http.read_timeout
+	Source string // Source where the invalid value was found, ex: env APP_HTTP_READ_TIMEOUT
+	Err    error
+}
+
+func (err *Error) Error() string {
+	if err.Source == "" {
+		return fmt.Sprintf("config: %s: %v", err.Key, err.Err)
+	}
+	return fmt.Sprintf("config: %s (%s): %v", err.Key, err.Source, err.Err)
+}
+
+func (err *Error) Unwrap() error {
+	return err.Err
+}
+
+// Errors all the errors found while loading the configuration
+type Errors []*Error
+
+func (errs Errors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (errs Errors) Unwrap() []error {
+	unwrapped := make([]error, len(errs))
+	for i, err := range errs {
+		unwrapped[i] = err
+	}
+	return unwrapped
+}
+
+// Loader fills configuration structs with values from the default tags, json files, environment
+// variables and command-line flags, the sources are applied in this order each one overriding the
+// previous.
+//
+// Fields are configured with the tags:
+//
+//	config:"name"   key of the field, defaults to the field name in snake case, "-" skips the field
+//	default:"value" value used when no source sets the field
+//	required:"true" reports ErrRequired when no source sets the field
+//	sep:";"         separator for lists in environment variables and flags, defaults to ","
+//
+// Nested structs prefix the keys of their fields with their own key, embedded structs without
+// a config tag don't. With EnvPrefix "APP_" the key http.read_timeout is looked up in the json files
+// as {"http":{"read_timeout":"5s"}}, in the environment as APP_HTTP_READ_TIMEOUT and in the flags
+// as -http.read-timeout.
+type Loader struct {
+	EnvPrefix          string                          // EnvPrefix prefix of the environment variables, ex: "APP_"
+	Files              []string                        // Files json files loaded in order
+	IgnoreMissingFiles bool                            // IgnoreMissingFiles skips files that don't exist
+	Args               []string                        // Args command-line arguments parsed as flags, ex: os.Args[1:]
+	LookupEnv          func(key string) (string, bool) // LookupEnv environment lookup, defaults to os.LookupEnv
+}
+
+type field struct {
+	key        []string
+	value      reflect.Value
+	def        string
+	hasDefault bool
+	required   bool
+	sep        string
+	set        bool
+}
+
+func (f *field) name() string {
+	return strings.Join(f.key, ".")
+}
+
+// Load fills targets, pointers to structs, returning Errors with all the missing and invalid keys
+func (loader *Loader) Load(targets ...interface{}) error {
+	var fields []*field
+	for _, target := range targets {
+		value := reflect.ValueOf(target)
+		if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+			panic(fmt.Errorf("config: target must be a non nil pointer to struct, got %T", target))
+		}
+		fields = collectFields(fields, nil, value.Elem())
+	}
+
+	var errs Errors
+	documents := loader.readFiles(&errs)
+
+	lookupEnv := loader.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+
+	for _, f := range fields {
+		if f.hasDefault {
+			if err := setString(f.value, f.def, f.sep); err != nil {
+				errs = append(errs, &Error{Key: f.name(), Source: "default", Err: err})
+			}
+			f.set = true
+		}
+
+		for i, document := range documents {
+			if raw, found := lookupJSON(document, f.key); found {
+				if err := setJSON(f.value, raw, f.sep); err != nil {
+					errs = append(errs, &Error{Key: f.name(), Source: "file " + loader.Files[i], Err: err})
+				}
+				f.set = true
+			}
+		}
+
+		envKey := loader.EnvPrefix + strings.ToUpper(strings.Join(f.key, "_"))
+		if val, found := lookupEnv(envKey); found {
+			if err := setString(f.value, val, f.sep); err != nil {
+				errs = append(errs, &Error{Key: f.name(), Source: "env " + envKey, Err: err})
+			}
+			f.set = true
+		}
+	}
+
+	if loader.Args != nil {
+		loader.parseFlags(fields, &errs)
+	}
+
+	for _, f := range fields {
+		if f.required && !f.set {
+			errs = append(errs, &Error{Key: f.name(), Err: ErrRequired})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Register loads targets and registers them in the registry, the configuration is then injected in the
+// fields of the same type of the values injected by the registry, see container.Registry.InjectValue, or
+// loaded by type, ex:
+//
+//	loader.Register(kernel.Registry, &HTTPConfig{})
+//	httpConfig := kernel.Registry.LoadType(reflect.TypeOf((*HTTPConfig)(nil))).(*HTTPConfig)
+func (loader *Loader) Register(registry *container.Registry, targets ...interface{}) error {
+	if err := loader.Load(targets...); err != nil {
+		return err
+	}
+	registry.WithValues(targets...)
+	return nil
+}
+
+func (loader *Loader) readFiles(errs *Errors) []map[string]interface{} {
+	documents := make([]map[string]interface{}, 0, len(loader.Files))
+	for _, fileName := range loader.Files {
+		document, err := readFile(fileName)
+		if err != nil {
+			if loader.IgnoreMissingFiles && errors.Is(err, os.ErrNotExist) {
+				err = nil
+			} else {
+				*errs = append(*errs, &Error{Key: fileName, Source: "file " + fileName, Err: err})
+			}
+		}
+		// appends nil documents to keep the index of the files
+		documents = append(documents, document)
+	}
+	return documents
+}
+
+func readFile(fileName string) (document map[string]interface{}, err error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+	err = decoder.Decode(&document)
+	return
+}
+
+func lookupJSON(document map[string]interface{}, key []string) (interface{}, bool) {
+	for i, name := range key {
+		raw, found := document[name]
+		if !found || raw == nil {
+			return nil, false
+		}
+		if i == len(key)-1 {
+			return raw, true
+		}
+		if document, found = raw.(map[string]interface{}); !found {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// flagValue sets all the fields sharing a flag name, invalid values are collected so that
+// the parse continues and reports all the errors at once
+type flagValue struct {
+	fields []*field
+	errs   *Errors
+}
+
+func (value *flagValue) String() string {
+	return ""
+}
+
+func (value *flagValue) Set(val string) error {
+	for _, f := range value.fields {
+		if err := setString(f.value, val, f.sep); err != nil {
+			*value.errs = append(*value.errs, &Error{Key: f.name(), Source: "flag -" + flagName(f.key), Err: err})
+		}
+		f.set = true
+	}
+	return nil
+}
+
+func (value *flagValue) IsBoolFlag() bool {
+	return value.fields[0].value.Kind() == reflect.Bool
+}
+
+func flagName(key []string) string {
+	return strings.ReplaceAll(strings.Join(key, "."), "_", "-")
+}
+
+func (loader *Loader) parseFlags(fields []*field, errs *Errors) {
+	flagSet := flag.NewFlagSet("config", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+
+	values := map[string]*flagValue{}
+	for _, f := range fields {
+		name := flagName(f.key)
+		if value, found := values[name]; found {
+			value.fields = append(value.fields, f)
+			continue
+		}
+		value := &flagValue{fields: []*field{f}, errs: errs}
+		values[name] = value
+		flagSet.Var(value, name, "")
+	}
+
+	if err := flagSet.Parse(loader.Args); err != nil {
+		*errs = append(*errs, &Error{Key: "flags", Source: "args", Err: err})
+	}
+}
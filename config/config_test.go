@@ -0,0 +1,162 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"github.com/CloudyKit/framework/container"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type databaseConfig struct {
+	URL      string `required:"true"`
+	PoolSize int    `default:"4"`
+}
+
+type commonConfig struct {
+	Debug bool
+}
+
+type appConfig struct {
+	commonConfig
+	Name         string        `default:"app"`
+	ReadTimeout  time.Duration `default:"5s"`
+	AllowedHosts []string      `sep:";"`
+	Ports        []int
+	Database     databaseConfig `config:"db"`
+	Cache        *databaseConfig
+	Ignored      string `config:"-"`
+}
+
+func environment(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, found := values[key]
+		return value, found
+	}
+}
+
+func TestLoader_Load(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(fileName, []byte(`{"name":"file","ports":[80,443],"db":{"url":"mongodb://file","pool_size":8},"cache":{"url":"redis://file"}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loader := &Loader{
+		EnvPrefix: "APP_",
+		Files:     []string{fileName, filepath.Join(t.TempDir(), "missing.json")},
+		Args:      []string{"-debug", "-db.pool-size", "16"},
+		LookupEnv: environment(map[string]string{
+			"APP_READ_TIMEOUT":  "1m",
+			"APP_ALLOWED_HOSTS": "a.com; b.com",
+			"APP_DB_URL":        "mongodb://env",
+			"APP_IGNORED":       "value",
+		}),
+		IgnoreMissingFiles: true,
+	}
+
+	var config appConfig
+	if err := loader.Load(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := appConfig{
+		commonConfig: commonConfig{Debug: true},
+		Name:         "file",
+		ReadTimeout:  time.Minute,
+		AllowedHosts: []string{"a.com", "b.com"},
+		Ports:        []int{80, 443},
+		Database:     databaseConfig{URL: "mongodb://env", PoolSize: 16},
+		Cache:        &databaseConfig{URL: "redis://file", PoolSize: 4},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("unexpected config:\n got %+v\nwant %+v", config, expected)
+	}
+}
+
+func TestLoader_LoadErrors(t *testing.T) {
+	loader := &Loader{
+		Args: []string{"-ports", "80,http"},
+		LookupEnv: environment(map[string]string{
+			"READ_TIMEOUT": "soon",
+			"CACHE_URL":    "redis://env",
+		}),
+	}
+
+	var config appConfig
+	err := loader.Load(&config)
+
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected Errors got %v", err)
+	}
+
+	keys := map[string]bool{}
+	for _, err := range errs {
+		keys[err.Key] = true
+	}
+	for _, key := range []string{"read_timeout", "ports", "db.url"} {
+		if !keys[key] {
+			t.Errorf("expected an error for key %s in:\n%v", key, err)
+		}
+	}
+	if len(errs) != 3 {
+		t.Errorf("expected 3 errors got %d:\n%v", len(errs), err)
+	}
+	if !errors.Is(err, ErrRequired) {
+		t.Errorf("expected ErrRequired in %v", err)
+	}
+}
+
+func TestLoader_Register(t *testing.T) {
+	registry := container.New()
+	loader := &Loader{LookupEnv: environment(map[string]string{"DB_URL": "mongodb://env", "CACHE_URL": "redis://env"})}
+
+	config := &appConfig{}
+	if err := loader.Register(registry, config); err != nil {
+		t.Fatal(err)
+	}
+
+	var injected *appConfig
+	registry.Load(&injected)
+	if injected != config {
+		t.Errorf("config was not registered in the registry")
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Name":        "name",
+		"ReadTimeout": "read_timeout",
+		"HTTPPort":    "http_port",
+		"URL":         "url",
+	} {
+		if got := snakeCase(name); got != expected {
+			t.Errorf("snakeCase(%q) = %q, want %q", name, got, expected)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// collectFields appends the fields of the struct value, nested structs are walked with their key as prefix
+func collectFields(fields []*field, prefix []string, value reflect.Value) []*field {
+	typ := value.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		structField := typ.Field(i)
+		if !structField.IsExported() && !(structField.Anonymous && isNested(structField.Type)) {
+			continue
+		}
+
+		name, tagged := structField.Tag.Lookup("config")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = snakeCase(structField.Name)
+		}
+		key := append(prefix[:len(prefix):len(prefix)], name)
+
+		fieldValue := value.Field(i)
+		if fieldValue.Kind() == reflect.Ptr && isNested(fieldValue.Type().Elem()) {
+			if fieldValue.IsNil() {
+				fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+			}
+			fieldValue = fieldValue.Elem()
+		}
+
+		if isNested(fieldValue.Type()) {
+			if structField.Anonymous && !tagged {
+				key = prefix
+			}
+			fields = collectFields(fields, key, fieldValue)
+			continue
+		}
+
+		f := &field{key: key, value: fieldValue, sep: ","}
+		f.def, f.hasDefault = structField.Tag.Lookup("default")
+		f.required = structField.Tag.Get("required") == "true"
+		if sep, found := structField.Tag.Lookup("sep"); found {
+			f.sep = sep
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// isNested reports if typ is a struct holding configuration fields
+func isNested(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && !reflect.PtrTo(typ).Implements(textUnmarshalerType)
+}
+
+// snakeCase converts a field name into a key, ex: ReadTimeout => read_timeout, HTTPPort => http_port
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				builder.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// ParseValue parses text into value the same way the environment values are parsed, lists are
+// split by sep, ex: "5s" into a time.Duration, "a,b" into a []string
+func ParseValue(value reflect.Value, text string, sep string) error {
+	return setString(value, text, sep)
+}
+
+// setString parses val into value, lists are split by sep
+func setString(value reflect.Value, val string, sep string) error {
+	if value.CanAddr() && value.Addr().Type().Implements(textUnmarshalerType) {
+		return value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(val))
+	}
+
+	if value.Type() == durationType {
+		duration, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(duration))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(value.Type().Elem())
+		if err := setString(elem.Elem(), val, sep); err != nil {
+			return err
+		}
+		value.Set(elem)
+	case reflect.String:
+		value.SetString(val)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(val, 0, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(val, 0, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(val, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(f)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			value.SetBytes([]byte(val))
+			return nil
+		}
+		var items []string
+		if strings.TrimSpace(val) != "" {
+			items = strings.Split(val, sep)
+		}
+		slice := reflect.MakeSlice(value.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setString(slice.Index(i), strings.TrimSpace(item), sep); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		value.Set(slice)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return nil
+}
+
+// setJSON sets a value decoded from a json file into value, strings and numbers are parsed
+// as the environment values, ex: durations are written as "5s"
+func setJSON(value reflect.Value, raw interface{}, sep string) error {
+	switch raw := raw.(type) {
+	case string:
+		return setString(value, raw, sep)
+	case json.Number:
+		return setString(value, raw.String(), sep)
+	case bool:
+		return setString(value, strconv.FormatBool(raw), sep)
+	case []interface{}:
+		if value.Kind() != reflect.Slice {
+			return fmt.Errorf("unexpected list for type %s", value.Type())
+		}
+		slice := reflect.MakeSlice(value.Type(), len(raw), len(raw))
+		for i, item := range raw {
+			if err := setJSON(slice.Index(i), item, sep); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		value.Set(slice)
+		return nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value.Addr().Interface())
+}
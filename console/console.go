@@ -0,0 +1,367 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/CloudyKit/framework/container"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// exit codes returned by Commands.Exit
+const (
+	ExitOK      = 0
+	ExitFailure = 1
+	ExitUsage   = 2
+)
+
+var CommandsType = reflect.TypeOf((*Commands)(nil))
+
+// GetCommands returns the commands registered in the registry, case there's no commands in the registry
+// a new one is registered
+func GetCommands(registry *container.Registry) *Commands {
+	if commands, _ := registry.LoadType(CommandsType).(*Commands); commands != nil {
+		return commands
+	}
+	commands := new(Commands)
+	registry.WithTypeAndValue(CommandsType, commands)
+	return commands
+}
+
+// Command a console command, commands are pointers to structs, before Run a copy of the
+// registered struct is made and its fields are set:
+//
+//	flag:"name"     the field is set by the flag -name
+//	arg:"name"      the field is set by the next positional argument, a slice field takes the remaining arguments
+//	usage:"text"    description of the flag or argument in the help
+//	default:"value" value used when the flag or argument is not in the command line
+//	required:"true" the command fails with a usage error when the flag or argument is missing
+//
+// the other exported fields are injected from a fork of the registry, the fork is disposed after Run,
+// flags and arguments are parsed as config values, see config.ParseValue
+type Command interface {
+	Run(ctx context.Context) error
+}
+
+// ErrHelp is returned by Commands.Parse when the help was requested and written
+var ErrHelp = errors.New("console: help requested")
+
+// UsageError invalid command line, ex: unknown command, flag or missing argument
+type UsageError struct {
+	Program string
+	Command string
+	Err     error
+}
+
+func (err *UsageError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *UsageError) Unwrap() error {
+	return err.Err
+}
+
+// ExitError an error carrying the exit code of the process, see Exit
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (err *ExitError) Error() string {
+	if err.Err == nil {
+		return fmt.Sprintf("exit status %d", err.Code)
+	}
+	return err.Err.Error()
+}
+
+func (err *ExitError) Unwrap() error {
+	return err.Err
+}
+
+// Exit returns an error making the process exit with code, err is reported when not nil
+func Exit(code int, err error) error {
+	return &ExitError{Code: code, Err: err}
+}
+
+// Definition a command registered with its name and the description shown in the help
+type Definition struct {
+	Name    string
+	Usage   string
+	Command Command
+
+	typ reflect.Type
+}
+
+// Commands holds the console commands, see Commands.Run
+type Commands struct {
+	Stdout io.Writer // Stdout output of the help and of the commands printing to it, defaults to os.Stdout
+	Stderr io.Writer // Stderr output of the errors, defaults to os.Stderr
+
+	mx          sync.RWMutex
+	definitions map[string]*Definition
+}
+
+func (commands *Commands) stdout() io.Writer {
+	if commands.Stdout == nil {
+		return os.Stdout
+	}
+	return commands.Stdout
+}
+
+func (commands *Commands) stderr() io.Writer {
+	if commands.Stderr == nil {
+		return os.Stderr
+	}
+	return commands.Stderr
+}
+
+// Add registers command with name, usage is the description shown in the help, the first line is
+// shown in the list of commands. Add panics when the name is taken, or the command is not a pointer
+// to a struct or has invalid flags or arguments
+func (commands *Commands) Add(name, usage string, command Command) {
+	typ := reflect.TypeOf(command)
+	if typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("console: command %s must be a pointer to a struct, got %s", name, typ))
+	}
+	if name == "" || name == "help" || strings.HasPrefix(name, "-") {
+		panic(fmt.Sprintf("console: invalid command name %q", name))
+	}
+	if _, err := collectParams(name, reflect.New(typ.Elem()).Elem()); err != nil {
+		panic(err)
+	}
+
+	commands.mx.Lock()
+	defer commands.mx.Unlock()
+	if _, taken := commands.definitions[name]; taken {
+		panic(fmt.Sprintf("console: command %s already added", name))
+	}
+	if commands.definitions == nil {
+		commands.definitions = map[string]*Definition{}
+	}
+	commands.definitions[name] = &Definition{Name: name, Usage: usage, Command: command, typ: typ.Elem()}
+}
+
+// Lookup returns the definition of the command name
+func (commands *Commands) Lookup(name string) (*Definition, bool) {
+	commands.mx.RLock()
+	defer commands.mx.RUnlock()
+	definition, found := commands.definitions[name]
+	return definition, found
+}
+
+// Names returns the sorted names of the commands
+func (commands *Commands) Names() []string {
+	commands.mx.RLock()
+	defer commands.mx.RUnlock()
+	names := make([]string, 0, len(commands.definitions))
+	for name := range commands.definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Invocation a command ready to run, see Commands.Parse
+type Invocation struct {
+	Definition *Definition
+	Command    Command             // Command the copy of the registered command with the flags, arguments and services set
+	Registry   *container.Registry // Registry fork of the registry injected in the command
+}
+
+// Run runs the command
+func (invocation *Invocation) Run(ctx context.Context) error {
+	return invocation.Command.Run(ctx)
+}
+
+// Dispose disposes the registry of the invocation
+func (invocation *Invocation) Dispose() {
+	invocation.Registry.Dispose()
+}
+
+// Parse parses the command line, args includes the program name, ex: os.Args. The help is written
+// and ErrHelp is returned for "help", "help <command>", "<command> -h" or an empty line, invalid
+// command lines return a *UsageError, the returned invocation must be disposed
+func (commands *Commands) Parse(registry *container.Registry, args []string) (*Invocation, error) {
+	program := "app"
+	if len(args) > 0 {
+		program = programName(args[0])
+		args = args[1:]
+	}
+
+	if len(args) == 0 || isHelpFlag(args[0]) {
+		commands.writeHelp(program)
+		return nil, ErrHelp
+	}
+
+	if args[0] == "help" {
+		if len(args) == 1 {
+			commands.writeHelp(program)
+			return nil, ErrHelp
+		}
+		definition, found := commands.Lookup(args[1])
+		if !found {
+			return nil, &UsageError{Program: program, Err: fmt.Errorf("unknown command %q", args[1])}
+		}
+		commands.writeCommandHelp(program, definition)
+		return nil, ErrHelp
+	}
+
+	definition, found := commands.Lookup(args[0])
+	if !found {
+		return nil, &UsageError{Program: program, Err: fmt.Errorf("unknown command %q", args[0])}
+	}
+
+	value := reflect.New(definition.typ)
+	value.Elem().Set(reflect.ValueOf(definition.Command).Elem())
+
+	params, err := collectParams(definition.Name, value.Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	fork := registry.Fork()
+	fork.InjectValue(value.Elem())
+
+	if err := params.parse(args[1:]); err != nil {
+		fork.Dispose()
+		if errors.Is(err, ErrHelp) {
+			commands.writeCommandHelp(program, definition)
+			return nil, ErrHelp
+		}
+		return nil, &UsageError{Program: program, Command: definition.Name, Err: err}
+	}
+
+	return &Invocation{Definition: definition, Command: value.Interface().(Command), Registry: fork}, nil
+}
+
+// Run parses and runs the command line, the exit code is returned, see Commands.Parse and Commands.Exit
+func (commands *Commands) Run(ctx context.Context, registry *container.Registry, args []string) int {
+	invocation, err := commands.Parse(registry, args)
+	if err != nil {
+		return commands.Exit(err)
+	}
+	defer invocation.Dispose()
+	return commands.Exit(invocation.Run(ctx))
+}
+
+// Exit writes err to Stderr and returns its exit code, ExitOK for nil and ErrHelp, ExitUsage for a
+// *UsageError, the code of an *ExitError or ExitFailure
+func (commands *Commands) Exit(err error) int {
+	if err == nil || errors.Is(err, ErrHelp) {
+		return ExitOK
+	}
+
+	var usageErr *UsageError
+	if errors.As(err, &usageErr) {
+		fmt.Fprintf(commands.stderr(), "error: %s\n", usageErr.Err)
+		if usageErr.Command != "" {
+			fmt.Fprintf(commands.stderr(), "Run '%s help %s' for usage.\n", usageErr.Program, usageErr.Command)
+		} else {
+			fmt.Fprintf(commands.stderr(), "Run '%s help' for the list of commands.\n", usageErr.Program)
+		}
+		return ExitUsage
+	}
+
+	code := ExitFailure
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		code = exitErr.Code
+		err = exitErr.Err
+	}
+	if err != nil {
+		fmt.Fprintf(commands.stderr(), "error: %s\n", err)
+	}
+	return code
+}
+
+func (commands *Commands) writeHelp(program string) {
+	w := tabwriter.NewWriter(commands.stdout(), 0, 4, 3, ' ', 0)
+	fmt.Fprintf(w, "Usage: %s <command> [flags] [arguments]\n\nCommands:\n", program)
+	for _, name := range commands.Names() {
+		definition, _ := commands.Lookup(name)
+		fmt.Fprintf(w, "  %s\t%s\n", name, firstLine(definition.Usage))
+	}
+	fmt.Fprintf(w, "  help\tShows the help of a command\n\nRun '%s help <command>' for the flags and arguments of a command.\n", program)
+	_ = w.Flush()
+}
+
+func (commands *Commands) writeCommandHelp(program string, definition *Definition) {
+	params, _ := collectParams(definition.Name, reflect.New(definition.typ).Elem())
+
+	w := tabwriter.NewWriter(commands.stdout(), 0, 4, 3, ' ', 0)
+	fmt.Fprintf(w, "Usage: %s %s", program, definition.Name)
+	if len(params.flags) > 0 {
+		fmt.Fprint(w, " [flags]")
+	}
+	for _, arg := range params.args {
+		fmt.Fprintf(w, " %s", arg.synopsis())
+	}
+	fmt.Fprintln(w)
+
+	if definition.Usage != "" {
+		fmt.Fprintf(w, "\n%s\n", definition.Usage)
+	}
+	if len(params.args) > 0 {
+		fmt.Fprint(w, "\nArguments:\n")
+		for _, arg := range params.args {
+			fmt.Fprintf(w, "  %s\t%s\n", arg.name, arg.describe())
+		}
+	}
+	if len(params.flags) > 0 {
+		fmt.Fprint(w, "\nFlags:\n")
+		for _, flag := range params.flags {
+			spec := "-" + flag.name
+			if typeName := flag.typeName(); typeName != "" {
+				spec += " " + typeName
+			}
+			fmt.Fprintf(w, "  %s\t%s\n", spec, flag.describe())
+		}
+	}
+	_ = w.Flush()
+}
+
+func programName(path string) string {
+	if i := strings.LastIndexAny(path, `/\`); i >= 0 {
+		path = path[i+1:]
+	}
+	return path
+}
+
+func isHelpFlag(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+func firstLine(text string) string {
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		return text[:i]
+	}
+	return text
+}
@@ -0,0 +1,175 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package console
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/CloudyKit/framework/container"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type reindexer struct {
+	name string
+}
+
+type reindexCommand struct {
+	Indexer *reindexer
+
+	Batch   int           `flag:"batch" default:"100" usage:"documents per batch"`
+	Timeout time.Duration `flag:"timeout" default:"1m"`
+	DryRun  bool          `flag:"dry-run" usage:"only report the changes"`
+	Tags    []string      `flag:"tag"`
+
+	Collection string   `arg:"collection" required:"true" usage:"collection to reindex"`
+	Fields     []string `arg:"fields"`
+
+	ran *reindexCommand
+}
+
+func (command *reindexCommand) Run(ctx context.Context) error {
+	*command.ran = *command
+	if command.Collection == "fail" {
+		return Exit(3, errors.New("reindex failed"))
+	}
+	return nil
+}
+
+func newCommands(ran *reindexCommand) (*Commands, *bytes.Buffer, *bytes.Buffer) {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	commands := &Commands{Stdout: stdout, Stderr: stderr}
+	commands.Add("reindex", "Reindexes a collection\nThe indexes are rebuilt in batches", &reindexCommand{ran: ran})
+	return commands, stdout, stderr
+}
+
+func TestCommands_Run(t *testing.T) {
+	registry := container.New()
+	defer registry.Dispose()
+	registry.WithValues(&reindexer{name: "mongo"})
+
+	ran := new(reindexCommand)
+	commands, _, stderr := newCommands(ran)
+
+	code := commands.Run(context.Background(), registry, []string{"/bin/app", "reindex", "-batch=10", "users", "--dry-run", "-tag", "a,b", "-tag=c", "name", "email"})
+	if code != ExitOK {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	}
+	if ran.Indexer == nil || ran.Indexer.name != "mongo" {
+		t.Errorf("service was not injected: %+v", ran.Indexer)
+	}
+	if ran.Batch != 10 || ran.Timeout != time.Minute || !ran.DryRun || ran.Collection != "users" {
+		t.Errorf("unexpected flags %+v", ran)
+	}
+	if !reflect.DeepEqual(ran.Tags, []string{"a", "b", "c"}) || !reflect.DeepEqual(ran.Fields, []string{"name", "email"}) {
+		t.Errorf("unexpected lists %v %v", ran.Tags, ran.Fields)
+	}
+
+	// each run works on a copy of the registered command
+	*ran = reindexCommand{}
+	if code := commands.Run(context.Background(), registry, []string{"app", "reindex", "posts"}); code != ExitOK {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	}
+	if ran.Batch != 100 || ran.DryRun || ran.Tags != nil || ran.Fields != nil {
+		t.Errorf("unexpected values from a previous run %+v", ran)
+	}
+
+	for _, test := range []struct {
+		args   []string
+		code   int
+		stderr string
+	}{
+		{args: []string{"app", "reindex", "fail"}, code: 3, stderr: "error: reindex failed"},
+		{args: []string{"app", "reindex"}, code: ExitUsage, stderr: "missing required argument collection"},
+		{args: []string{"app", "reindex", "-batch", "x", "users"}, code: ExitUsage, stderr: `invalid value "x" for flag -batch`},
+		{args: []string{"app", "reindex", "-unknown", "users"}, code: ExitUsage, stderr: "Run 'app help reindex' for usage."},
+		{args: []string{"app", "migrate"}, code: ExitUsage, stderr: `unknown command "migrate"`},
+	} {
+		stderr.Reset()
+		if code := commands.Run(context.Background(), registry, test.args); code != test.code {
+			t.Errorf("%v: expected exit code %d, got %d", test.args, test.code, code)
+		}
+		if !strings.Contains(stderr.String(), test.stderr) {
+			t.Errorf("%v: expected %q in the output, got %q", test.args, test.stderr, stderr)
+		}
+	}
+}
+
+func TestCommands_Help(t *testing.T) {
+	registry := container.New()
+	defer registry.Dispose()
+
+	commands, stdout, _ := newCommands(new(reindexCommand))
+
+	if code := commands.Run(context.Background(), registry, []string{"app"}); code != ExitOK {
+		t.Errorf("unexpected exit code %d", code)
+	}
+	if output := stdout.String(); !strings.Contains(output, "reindex   Reindexes a collection\n") || strings.Contains(output, "batches") {
+		t.Errorf("unexpected commands help %q", output)
+	}
+
+	for _, args := range [][]string{{"app", "help", "reindex"}, {"app", "reindex", "-h"}} {
+		stdout.Reset()
+		if code := commands.Run(context.Background(), registry, args); code != ExitOK {
+			t.Errorf("%v: unexpected exit code %d", args, code)
+		}
+		output := stdout.String()
+		for _, expected := range []string{
+			"Usage: app reindex [flags] <collection> [fields...]",
+			"The indexes are rebuilt in batches",
+			"-batch int",
+			"documents per batch (default 100)",
+			"-dry-run ",
+			"collection to reindex (required)",
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("%v: expected %q in the help %q", args, expected, output)
+			}
+		}
+	}
+}
+
+type invalidCommand struct {
+	First []string `arg:"first"`
+	Last  string   `arg:"last"`
+}
+
+func (command *invalidCommand) Run(ctx context.Context) error {
+	return nil
+}
+
+func TestCommands_AddInvalid(t *testing.T) {
+	for name, command := range map[string]Command{"invalid": &invalidCommand{}, "help": &reindexCommand{}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			new(Commands).Add(name, "", command)
+		}()
+	}
+}
@@ -0,0 +1,261 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package console
+
+import (
+	"fmt"
+	"github.com/CloudyKit/framework/config"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// param a flag or a positional argument bound to a field of a command
+type param struct {
+	name       string
+	usage      string
+	def        string
+	hasDefault bool
+	required   bool
+	flag       bool
+	value      reflect.Value
+	set        bool
+}
+
+type params struct {
+	flags []*param
+	args  []*param
+}
+
+// collectParams collects the flags and arguments of the command struct value, see Command
+func collectParams(command string, value reflect.Value) (*params, error) {
+	params := new(params)
+	typ := value.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		structField := typ.Field(i)
+		flagName, isFlag := structField.Tag.Lookup("flag")
+		argName, isArg := structField.Tag.Lookup("arg")
+		if !isFlag && !isArg {
+			continue
+		}
+		if !structField.IsExported() {
+			return nil, fmt.Errorf("console: command %s: field %s is not exported", command, structField.Name)
+		}
+
+		p := &param{usage: structField.Tag.Get("usage"), flag: isFlag, value: value.Field(i)}
+		p.def, p.hasDefault = structField.Tag.Lookup("default")
+		p.required = structField.Tag.Get("required") == "true"
+
+		if isFlag {
+			p.name = strings.TrimLeft(flagName, "-")
+			if p.name == "" || isHelpFlag("-"+p.name) || params.flag(p.name) != nil {
+				return nil, fmt.Errorf("console: command %s: invalid or duplicated flag %q in field %s", command, flagName, structField.Name)
+			}
+			params.flags = append(params.flags, p)
+			continue
+		}
+
+		p.name = argName
+		if p.name == "" {
+			p.name = strings.ToLower(structField.Name)
+		}
+		if n := len(params.args); n > 0 && params.args[n-1].isList() {
+			return nil, fmt.Errorf("console: command %s: argument %s follows the list argument %s", command, p.name, params.args[n-1].name)
+		}
+		params.args = append(params.args, p)
+	}
+	return params, nil
+}
+
+func (params *params) flag(name string) *param {
+	for _, flag := range params.flags {
+		if flag.name == name {
+			return flag
+		}
+	}
+	return nil
+}
+
+// parse sets the fields from the command line arguments, flags may appear before or after
+// the arguments, "--" ends the flags, list flags can be repeated
+func (params *params) parse(args []string) error {
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			positional = append(positional, arg)
+			continue
+		}
+		if isHelpFlag(arg) {
+			return ErrHelp
+		}
+
+		name, text, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		flag := params.flag(name)
+		if flag == nil {
+			return fmt.Errorf("unknown flag -%s", name)
+		}
+		if !hasValue {
+			if flag.isBool() {
+				text = "true"
+			} else if i+1 < len(args) {
+				i++
+				text = args[i]
+			} else {
+				return fmt.Errorf("flag -%s needs a value", name)
+			}
+		}
+		if err := flag.setValue(text); err != nil {
+			return fmt.Errorf("invalid value %q for flag -%s: %w", text, name, err)
+		}
+	}
+
+	for _, arg := range params.args {
+		for len(positional) > 0 {
+			if err := arg.setValue(positional[0]); err != nil {
+				return fmt.Errorf("invalid value %q for argument %s: %w", positional[0], arg.name, err)
+			}
+			positional = positional[1:]
+			if !arg.isList() {
+				break
+			}
+		}
+	}
+	if len(positional) > 0 {
+		return fmt.Errorf("unexpected argument %q", positional[0])
+	}
+
+	for _, p := range append(params.flags[:len(params.flags):len(params.flags)], params.args...) {
+		if p.set {
+			continue
+		}
+		if p.required {
+			return fmt.Errorf("missing required %s", p.display())
+		}
+		if p.hasDefault {
+			if err := config.ParseValue(p.value, p.def, ","); err != nil {
+				return fmt.Errorf("invalid default %q for %s: %w", p.def, p.display(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// setValue parses text into the field, the values of list flags are split by "," and appended,
+// the values of a list argument are appended as is
+func (p *param) setValue(text string) error {
+	if !p.isList() {
+		p.set = true
+		return config.ParseValue(p.value, text, ",")
+	}
+
+	var items reflect.Value
+	if p.flag {
+		items = reflect.New(p.value.Type()).Elem()
+		if err := config.ParseValue(items, text, ","); err != nil {
+			return err
+		}
+	} else {
+		items = reflect.MakeSlice(p.value.Type(), 1, 1)
+		if err := config.ParseValue(items.Index(0), text, ","); err != nil {
+			return err
+		}
+	}
+
+	if !p.set {
+		// the registered command may carry a list, it is replaced by the command line values
+		p.value.Set(reflect.Zero(p.value.Type()))
+		p.set = true
+	}
+	p.value.Set(reflect.AppendSlice(p.value, items))
+	return nil
+}
+
+func (p *param) isBool() bool {
+	return p.value.Kind() == reflect.Bool
+}
+
+func (p *param) isList() bool {
+	return p.value.Kind() == reflect.Slice && p.value.Type().Elem().Kind() != reflect.Uint8
+}
+
+func (p *param) display() string {
+	if p.flag {
+		return "flag -" + p.name
+	}
+	return "argument " + p.name
+}
+
+// synopsis returns the argument as shown in the usage line, ex: <name>, [name], [names...]
+func (p *param) synopsis() string {
+	name := p.name
+	if p.isList() {
+		name += "..."
+	}
+	if p.required {
+		return "<" + name + ">"
+	}
+	return "[" + name + "]"
+}
+
+// typeName returns the name of the flag value shown in the help, bool flags have no value
+func (p *param) typeName() string {
+	typ := p.value.Type()
+	switch {
+	case typ == durationType:
+		return "duration"
+	case p.isList():
+		return "list"
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return ""
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	}
+	return "value"
+}
+
+// describe returns the usage of the param with its default and required notes
+func (p *param) describe() string {
+	description := p.usage
+	switch {
+	case p.required:
+		description += " (required)"
+	case p.hasDefault:
+		description += fmt.Sprintf(" (default %s)", p.def)
+	}
+	return strings.TrimSpace(description)
+}
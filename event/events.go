@@ -120,6 +120,62 @@ type Dispatcher struct {
 	parent        *Dispatcher
 	mx            sync.RWMutex
 	subscriptions []subscriptionGroups
+	observers     []Observer
+	interceptors  []Interceptor
+}
+
+// Observer is notified after every event dispatched by the dispatcher or by its children, see Dispatcher.Observe
+type Observer func(eventName string, canceled bool, err error)
+
+// Observe adds an observer to the dispatcher, ex: count the dispatched events
+func (dispatcher *Dispatcher) Observe(observer Observer) *Dispatcher {
+	dispatcher.assert()
+	dispatcher.mx.Lock()
+	dispatcher.observers = append(dispatcher.observers, observer)
+	dispatcher.mx.Unlock()
+	return dispatcher
+}
+
+// Interceptor wraps the dispatch of the events, dispatch runs the event handlers and must be called,
+// ex: measure the time spent by the handlers, see Dispatcher.Intercept
+type Interceptor func(registry *container.Registry, eventName string, dispatch func() (bool, error)) (bool, error)
+
+// Intercept adds an interceptor to the dispatcher, interceptors of the dispatcher and of its parents
+// wrap every dispatched event, the first added is the outermost
+func (dispatcher *Dispatcher) Intercept(interceptor Interceptor) *Dispatcher {
+	dispatcher.assert()
+	dispatcher.mx.Lock()
+	dispatcher.interceptors = append(dispatcher.interceptors, interceptor)
+	dispatcher.mx.Unlock()
+	return dispatcher
+}
+
+// intercept wraps dispatch with the interceptors of the dispatcher and of its parents
+func (dispatcher *Dispatcher) intercept(registry *container.Registry, eventName string, dispatch func() (bool, error)) func() (bool, error) {
+	for ; dispatcher != nil; dispatcher = dispatcher.parent {
+		dispatcher.mx.RLock()
+		interceptors := dispatcher.interceptors
+		dispatcher.mx.RUnlock()
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], dispatch
+			dispatch = func() (bool, error) {
+				return interceptor(registry, eventName, next)
+			}
+		}
+	}
+	return dispatch
+}
+
+// notify notifies the observers of the dispatcher and of its parents
+func (dispatcher *Dispatcher) notify(eventName string, canceled bool, err error) {
+	for ; dispatcher != nil; dispatcher = dispatcher.parent {
+		dispatcher.mx.RLock()
+		observers := dispatcher.observers
+		dispatcher.mx.RUnlock()
+		for _, observer := range observers {
+			observer(eventName, canceled, err)
+		}
+	}
 }
 
 func (dispatcher *Dispatcher) Inherit() *Dispatcher {
@@ -261,5 +317,9 @@ func (dispatcher *Dispatcher) emit(eventName string, event Payload) (canceled bo
 // and cancel the event propagation
 func (dispatcher *Dispatcher) Dispatch(registry *container.Registry, eventName string, event Payload) (bool, error) {
 	event.init(registry, eventName)
-	return dispatcher.emit(eventName, event)
+	canceled, err := dispatcher.intercept(registry, eventName, func() (bool, error) {
+		return dispatcher.emit(eventName, event)
+	})()
+	dispatcher.notify(eventName, canceled, err)
+	return canceled, err
 }
@@ -165,3 +165,28 @@ func BenchmarkManager_SubscribeEmit(b *testing.B) {
 	})
 	b.Log("NumericId of handlers", len(bench_events.subscriptions[0].handlers))
 }
+
+func TestDispatcher_Observe(t *testing.T) {
+
+	parent := NewDispatcher()
+	events := parent.Inherit()
+
+	var observed []string
+	parent.Observe(func(eventName string, canceled bool, err error) {
+		if canceled {
+			eventName += " canceled"
+		}
+		observed = append(observed, eventName)
+	})
+
+	events.Subscribe("testCanceled", func(tc *TestContext) {
+		tc.Cancel()
+	})
+
+	events.Dispatch(nil, "testRunning", new(TestContext))
+	events.Dispatch(nil, "testCanceled", new(TestContext))
+
+	if len(observed) != 2 || observed[0] != "testRunning" || observed[1] != "testCanceled canceled" {
+		t.Fatalf("unexpected observed events %v", observed)
+	}
+}
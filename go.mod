@@ -1,6 +1,6 @@
 module github.com/CloudyKit/framework
 
-go 1.20
+go 1.21
 
 require (
 	github.com/CloudyKit/jet/v6 v6.1.0
@@ -0,0 +1,79 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package health
+
+import (
+	"encoding/json"
+	"github.com/CloudyKit/framework/app"
+	"net/http"
+	"time"
+)
+
+// Component serves the liveness and readiness probes, the endpoints respond with a json Report
+// and the status 200 when all the checks are up or 503 otherwise, the endpoints don't run the
+// kernel filters.
+type Component struct {
+	LivenessPath  string        // LivenessPath defaults to /healthz
+	ReadinessPath string        // ReadinessPath defaults to /readyz
+	Timeout       time.Duration // Timeout default max time given to a checker, see Checks.Timeout
+	CacheTTL      time.Duration // CacheTTL default time a result is reused, see Checks.CacheTTL
+}
+
+func (component *Component) Bootstrap(a *app.Kernel) {
+	if component.LivenessPath == "" {
+		component.LivenessPath = "/healthz"
+	}
+	if component.ReadinessPath == "" {
+		component.ReadinessPath = "/readyz"
+	}
+
+	checks := GetChecks(a.Registry)
+	if component.Timeout > 0 {
+		checks.Timeout = component.Timeout
+	}
+	if component.CacheTTL > 0 {
+		checks.CacheTTL = component.CacheTTL
+	}
+
+	a.Mount(component.LivenessPath, Handler(checks, true))
+	a.Mount(component.ReadinessPath, Handler(checks, false))
+}
+
+// Handler returns a http.Handler running checks, see Checks.Run
+func Handler(checks *Checks, liveness bool) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		report := checks.Run(r.Context(), liveness)
+
+		status := http.StatusOK
+		if report.Status != StatusUp {
+			status = http.StatusServiceUnavailable
+		}
+
+		rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+		rw.Header().Set("Cache-Control", "no-store")
+		rw.WriteHeader(status)
+		if r.Method != http.MethodHead {
+			_ = json.NewEncoder(rw).Encode(report)
+		}
+	})
+}
@@ -0,0 +1,229 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/CloudyKit/framework/container"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	DefaultTimeout = 5 * time.Second
+)
+
+var ChecksType = reflect.TypeOf((*Checks)(nil))
+
+// GetChecks returns the checks registered in the registry, case there's no checks in the registry
+// a new one is registered, components can add checks before or after the health component is bootstrapped
+func GetChecks(registry *container.Registry) *Checks {
+	if checks, _ := registry.LoadType(ChecksType).(*Checks); checks != nil {
+		return checks
+	}
+	checks := new(Checks)
+	registry.WithTypeAndValue(ChecksType, checks)
+	return checks
+}
+
+// Checker checks a dependency of the app, ex: ping a database
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc func implementing Checker interface
+type CheckerFunc func(ctx context.Context) error
+
+func (fn CheckerFunc) Check(ctx context.Context) error {
+	return fn(ctx)
+}
+
+// Check a named checker and its settings
+type Check struct {
+	Name     string
+	Checker  Checker
+	Timeout  time.Duration // Timeout max time given to the checker, defaults to Checks.Timeout
+	CacheTTL time.Duration // CacheTTL time the last result is reused, defaults to Checks.CacheTTL
+	Liveness bool          // Liveness the check is also run by the liveness probe, failing will restart the app
+
+	mx        sync.Mutex
+	result    Result
+	checkedAt time.Time
+}
+
+// Result the outcome of a check
+type Result struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report the outcome of the checks, Status is down when any check is down
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Checks holds the registered checks
+type Checks struct {
+	Timeout  time.Duration // Timeout default max time given to a checker, defaults to DefaultTimeout
+	CacheTTL time.Duration // CacheTTL default time a result is reused, zero runs the checks on every probe
+
+	mx     sync.RWMutex
+	checks []*Check
+}
+
+// Add adds a readiness check named name
+func (checks *Checks) Add(name string, checker Checker) {
+	checks.AddCheck(&Check{Name: name, Checker: checker})
+}
+
+// AddFunc adds a readiness func check named name
+func (checks *Checks) AddFunc(name string, fn func(ctx context.Context) error) {
+	checks.Add(name, CheckerFunc(fn))
+}
+
+// AddCheck adds check, a check with the same name is replaced
+func (checks *Checks) AddCheck(check *Check) {
+	checks.mx.Lock()
+	defer checks.mx.Unlock()
+	for i, c := range checks.checks {
+		if c.Name == check.Name {
+			checks.checks[i] = check
+			return
+		}
+	}
+	checks.checks = append(checks.checks, check)
+}
+
+// Names returns the names of the registered checks
+func (checks *Checks) Names() []string {
+	checks.mx.RLock()
+	defer checks.mx.RUnlock()
+	names := make([]string, len(checks.checks))
+	for i, check := range checks.checks {
+		names[i] = check.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run runs the checks concurrently, liveness selects only the checks flagged as Liveness
+func (checks *Checks) Run(ctx context.Context, liveness bool) Report {
+	checks.mx.RLock()
+	selected := make([]*Check, 0, len(checks.checks))
+	for _, check := range checks.checks {
+		if !liveness || check.Liveness {
+			selected = append(selected, check)
+		}
+	}
+	checks.mx.RUnlock()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(selected))}
+	results := make([]Result, len(selected))
+
+	var wg sync.WaitGroup
+	for i, check := range selected {
+		wg.Add(1)
+		go func(i int, check *Check) {
+			defer wg.Done()
+			results[i] = checks.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for i, check := range selected {
+		report.Checks[check.Name] = results[i]
+		if results[i].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+// run runs check or returns the cached result
+func (checks *Checks) run(ctx context.Context, check *Check) Result {
+	check.mx.Lock()
+	defer check.mx.Unlock()
+
+	ttl := check.CacheTTL
+	if ttl == 0 {
+		ttl = checks.CacheTTL
+	}
+	if ttl > 0 && !check.checkedAt.IsZero() && time.Since(check.checkedAt) < ttl {
+		return check.result
+	}
+
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = checks.Timeout
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	start := time.Now()
+	err := runTimeout(ctx, timeout, check.Checker)
+
+	check.checkedAt = time.Now()
+	check.result = Result{Status: StatusUp, Duration: check.checkedAt.Sub(start).String(), CheckedAt: check.checkedAt}
+	if err != nil {
+		check.result.Status = StatusDown
+		check.result.Error = err.Error()
+	}
+	return check.result
+}
+
+var errTimeout = errors.New("health: check timed out")
+
+// runTimeout runs checker returning errTimeout case the checker doesn't return within the timeout,
+// checkers ignoring the ctx keep running in background
+func runTimeout(ctx context.Context, timeout time.Duration, checker Checker) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- fmt.Errorf("health: check panicked: %v", recovered)
+			}
+		}()
+		done <- checker.Check(ctx)
+	}()
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = errTimeout
+	}
+	return
+}
@@ -0,0 +1,91 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/CloudyKit/framework/app"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func probe(t *testing.T, handler http.Handler, path string) (int, Report) {
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", path, nil))
+
+	var report Report
+	if err := json.Unmarshal(rw.Body.Bytes(), &report); err != nil {
+		t.Fatalf("invalid report %q: %v", rw.Body.String(), err)
+	}
+	return rw.Code, report
+}
+
+func TestComponent(t *testing.T) {
+	kernel := app.New()
+
+	var calls int32
+	checks := GetChecks(kernel.Registry)
+	checks.AddCheck(&Check{Name: "process", Liveness: true, Checker: CheckerFunc(func(ctx context.Context) error {
+		return nil
+	})})
+	checks.AddFunc("database", func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return errors.New("connection refused")
+	})
+	checks.AddCheck(&Check{Name: "slow", Timeout: 10 * time.Millisecond, Checker: CheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})})
+
+	kernel.Bootstrap(&Component{CacheTTL: time.Minute})
+
+	status, report := probe(t, kernel, "/healthz")
+	if status != http.StatusOK || report.Status != StatusUp || len(report.Checks) != 1 {
+		t.Errorf("unexpected liveness %d %+v", status, report)
+	}
+
+	status, report = probe(t, kernel, "/readyz")
+	if status != http.StatusServiceUnavailable || report.Status != StatusDown {
+		t.Errorf("unexpected readiness %d %+v", status, report)
+	}
+	if result := report.Checks["database"]; result.Status != StatusDown || result.Error != "connection refused" {
+		t.Errorf("unexpected database result %+v", result)
+	}
+	if result := report.Checks["slow"]; result.Status != StatusDown || result.Error != errTimeout.Error() {
+		t.Errorf("slow check should time out, got %+v", result)
+	}
+	if result := report.Checks["process"]; result.Status != StatusUp {
+		t.Errorf("unexpected process result %+v", result)
+	}
+
+	probe(t, kernel, "/readyz")
+	if calls != 1 {
+		t.Errorf("results should be cached, check ran %d times", calls)
+	}
+}
@@ -0,0 +1,132 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/CloudyKit/framework/app"
+	"github.com/CloudyKit/framework/request"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// DefaultHeader header carrying the request id
+const DefaultHeader = "X-Request-ID"
+
+// Component places a structured logger into each request registry, the logger is enriched with the
+// request id, route name, method and path, the request id is propagated from the request header or
+// generated and is sent back in the response header, after the request one access log line is emitted
+// with the status, bytes written and latency. Components bootstrapped after this one and the routes
+// added after it get the request logger with request.Context.Logger or request.GetLogger
+type Component struct {
+	Logger           *slog.Logger  // Logger root logger, defaults to slog.Default
+	Header           string        // Header request id header, defaults to X-Request-ID
+	Generate         func() string // Generate generates request ids, defaults to 16 random bytes hex encoded
+	IgnoreIncoming   bool          // IgnoreIncoming always generates the request id, ex: the app is not behind a trusted proxy
+	DisableAccessLog bool          // DisableAccessLog disables the access log line
+	AccessLevel      slog.Level    // AccessLevel level of the access log line, defaults to info
+}
+
+func (component *Component) Bootstrap(a *app.Kernel) {
+	if component.Logger == nil {
+		component.Logger = slog.Default()
+	}
+	if component.Header == "" {
+		component.Header = DefaultHeader
+	}
+	if component.Generate == nil {
+		component.Generate = NewRequestID
+	}
+
+	a.Registry.WithTypeAndValue(request.LoggerType, component.Logger)
+	app.GetKernel(a.Registry).BindFilterHandlers(component)
+}
+
+func (component *Component) Handle(c *request.Context) {
+	start := time.Now()
+
+	id := c.Request.Header.Get(component.Header)
+	if component.IgnoreIncoming || !validRequestID(id) {
+		id = component.Generate()
+	}
+	c.Request = request.WithRequestID(c.Request, id)
+	c.Response.Header().Set(component.Header, id)
+
+	logger := component.Logger.With(
+		slog.String("request_id", id),
+		slog.String("route", c.Name),
+		slog.String("method", c.Request.Method),
+		slog.String("path", c.Request.URL.Path),
+	)
+	c.Registry.WithTypeAndValue(request.LoggerType, logger)
+
+	writer := &request.StatusWriter{ResponseWriter: c.Response}
+	c.Response = writer
+
+	// a panic without a response is logged as an internal server error, the panic is
+	// not recovered here so that the kernel error handler receives it
+	completed := false
+	defer func() {
+		c.Response = writer.ResponseWriter
+		if component.DisableAccessLog {
+			return
+		}
+		status := writer.Status
+		if status == 0 {
+			status = http.StatusOK
+			if !completed {
+				status = http.StatusInternalServerError
+			}
+		}
+		logger.LogAttrs(c.Request.Context(), component.AccessLevel, "request",
+			slog.Int("status", status),
+			slog.Int64("bytes", writer.Bytes),
+			slog.Duration("latency", time.Since(start)),
+		)
+	}()
+
+	c.Next()
+	completed = true
+}
+
+// NewRequestID generates a random request id
+func NewRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID reports if an incoming request id is safe to be logged and sent back
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/CloudyKit/framework/app"
+	"github.com/CloudyKit/framework/request"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestComponent(t *testing.T) {
+	var output bytes.Buffer
+	kernel := app.New()
+	kernel.Bootstrap(&Component{
+		Logger:   slog.New(slog.NewJSONHandler(&output, nil)),
+		Generate: func() string { return "generated" },
+	})
+	kernel.AddHandlerName("users.show", "GET", "/users/:id", request.HandlerFunc(func(c *request.Context) {
+		c.Logger().Info("showing user")
+		c.Response.WriteHeader(http.StatusAccepted)
+		c.WriteString(c.RequestID())
+	}))
+
+	r := httptest.NewRequest("GET", "/users/1", nil)
+	r.Header.Set(DefaultHeader, "incoming-id")
+	rw := httptest.NewRecorder()
+	kernel.ServeHTTP(rw, r)
+
+	if rw.Body.String() != "incoming-id" || rw.Header().Get(DefaultHeader) != "incoming-id" {
+		t.Errorf("incoming request id should be propagated, got body %q header %q", rw.Body.String(), rw.Header().Get(DefaultHeader))
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected a handler line and an access line got:\n%s", output.String())
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["request_id"] != "incoming-id" || entry["route"] != "users.show" || entry["method"] != "GET" || entry["path"] != "/users/1" {
+		t.Errorf("handler logger is not enriched: %s", lines[0])
+	}
+
+	entry = nil
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["msg"] != "request" || entry["status"] != float64(http.StatusAccepted) || entry["bytes"] != float64(len("incoming-id")) || entry["latency"] == nil {
+		t.Errorf("unexpected access log line: %s", lines[1])
+	}
+
+	r = httptest.NewRequest("GET", "/users/1", nil)
+	r.Header.Set(DefaultHeader, "invalid id")
+	rw = httptest.NewRecorder()
+	kernel.ServeHTTP(rw, r)
+	if rw.Header().Get(DefaultHeader) != "generated" {
+		t.Errorf("invalid request id should be replaced, got %q", rw.Header().Get(DefaultHeader))
+	}
+	output.Reset()
+	kernel.AddHandler("GET", "/panic", request.HandlerFunc(func(c *request.Context) {
+		panic("failed")
+	}))
+	kernel.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/panic", nil))
+	if !strings.Contains(output.String(), `"msg":"request"`) || !strings.Contains(output.String(), `"status":500`) {
+		t.Errorf("panicking requests should be logged as internal server errors, got:\n%s", output.String())
+	}
+}
@@ -0,0 +1,131 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package metrics
+
+import (
+	"github.com/CloudyKit/framework/app"
+	"github.com/CloudyKit/framework/container"
+	"github.com/CloudyKit/framework/event"
+	"github.com/CloudyKit/framework/request"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Component instruments the routes added after it and the kernel events, and serves the metrics
+// in the prometheus text format, the metrics endpoint doesn't run the kernel filters.
+//
+//	http_requests_total{route,method,status}          counter of the handled requests
+//	http_request_duration_seconds{route,method}       histogram of the request latencies
+//	http_requests_in_flight                           gauge of the requests being handled
+//	events_dispatched_total{event,result}             counter of the dispatched events
+//
+// the route label is the route name, see request.Context.Name
+type Component struct {
+	Registry *Registry // Registry defaults to a new registry
+	Path     string    // Path metrics endpoint, defaults to /metrics
+	Buckets  []float64 // Buckets request latency buckets, defaults to DefaultBuckets
+}
+
+func (component *Component) Bootstrap(a *app.Kernel) {
+	if component.Registry == nil {
+		component.Registry = NewRegistry()
+	}
+	if component.Path == "" {
+		component.Path = "/metrics"
+	}
+
+	a.Registry.WithTypeAndValue(RegistryType, component.Registry)
+	app.GetKernel(a.Registry).BindFilterHandlers(Instrument(component.Registry, component.Buckets))
+
+	events := component.Registry.Counter("events_dispatched_total", "Number of dispatched events.", "event", "result")
+	event.GetDispatcher(a.Registry).Observe(func(eventName string, canceled bool, err error) {
+		result := "ok"
+		if err != nil {
+			result = "error"
+		} else if canceled {
+			result = "canceled"
+		}
+		events.Inc(eventName, result)
+	})
+
+	a.Mount(component.Path, component.Registry.Handler())
+}
+
+// ODMOperationsMetric histogram of the odm manager operation latencies in seconds, see ODMObserver
+const ODMOperationsMetric = "odm_operation_duration_seconds"
+
+// ODMObserver records the latency of the odm manager operations in the metrics registry provided by the
+// manager registry, see odm.Observer and ODMOperationsMetric
+type ODMObserver struct{}
+
+func (ODMObserver) ObserveOperation(registry *container.Registry, collection, operation string) func(err error) {
+	metrics := GetRegistry(registry)
+	if metrics == nil {
+		return func(error) {}
+	}
+	start := time.Now()
+	return func(err error) {
+		status := "ok"
+		if err != nil {
+			status = "error"
+		}
+		metrics.Histogram(ODMOperationsMetric, "Latency of the odm manager operations in seconds.", nil, "collection", "operation", "status").
+			Observe(time.Since(start).Seconds(), collection, operation, status)
+	}
+}
+
+// Instrument returns a filter recording the request metrics of the routes, see Component
+func Instrument(registry *Registry, buckets []float64) request.Handler {
+	requests := registry.Counter("http_requests_total", "Number of handled requests.", "route", "method", "status")
+	durations := registry.Histogram("http_request_duration_seconds", "Latency of the requests in seconds.", buckets, "route", "method")
+	inFlight := registry.Gauge("http_requests_in_flight", "Number of requests being handled.")
+
+	return request.HandlerFunc(func(c *request.Context) {
+		start := time.Now()
+		inFlight.Inc()
+
+		writer := &request.StatusWriter{ResponseWriter: c.Response}
+		c.Response = writer
+
+		// a panic without a response is recorded as an internal server error, the panic is
+		// not recovered here so that the kernel error handler receives it
+		completed := false
+		defer func() {
+			c.Response = writer.ResponseWriter
+			status := writer.Status
+			if status == 0 {
+				status = http.StatusOK
+				if !completed {
+					status = http.StatusInternalServerError
+				}
+			}
+			inFlight.Dec()
+			requests.Inc(c.Name, c.Request.Method, strconv.Itoa(status))
+			durations.Observe(time.Since(start).Seconds(), c.Name, c.Request.Method)
+		}()
+
+		c.Next()
+		completed = true
+	})
+}
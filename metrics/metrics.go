@@ -0,0 +1,336 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/CloudyKit/framework/container"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets default histogram buckets in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+var RegistryType = reflect.TypeOf((*Registry)(nil))
+
+// GetRegistry returns the metrics registry provided by the registry or nil
+func GetRegistry(registry *container.Registry) *Registry {
+	metrics, _ := registry.LoadType(RegistryType).(*Registry)
+	return metrics
+}
+
+const (
+	kindCounter   = "counter"
+	kindGauge     = "gauge"
+	kindHistogram = "histogram"
+)
+
+// Registry holds the metrics and writes them in the prometheus text exposition format
+type Registry struct {
+	mx      sync.Mutex
+	metrics map[string]*metric
+}
+
+// NewRegistry creates a new metrics registry
+func NewRegistry() *Registry {
+	return &Registry{metrics: map[string]*metric{}}
+}
+
+// metric a family of series sharing the name and the label names
+type metric struct {
+	name       string
+	help       string
+	kind       string
+	labelNames []string
+	buckets    []float64
+
+	mx     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64  // counter and gauge value, histogram sum
+	count       uint64   // histogram count
+	buckets     []uint64 // histogram counts per bucket, not cumulative
+}
+
+func (registry *Registry) metric(name, help, kind string, buckets []float64, labelNames []string) *metric {
+	registry.mx.Lock()
+	defer registry.mx.Unlock()
+
+	if m, found := registry.metrics[name]; found {
+		if m.kind != kind || !sameLabels(m.labelNames, labelNames) {
+			panic(fmt.Errorf("metrics: %s already registered as %s with labels %v", name, m.kind, m.labelNames))
+		}
+		return m
+	}
+
+	if buckets != nil {
+		buckets = append([]float64(nil), buckets...)
+		sort.Float64s(buckets)
+	}
+	m := &metric{name: name, help: help, kind: kind, buckets: buckets, labelNames: labelNames, series: map[string]*series{}}
+	registry.metrics[name] = m
+	return m
+}
+
+// sameLabels reports whether the label names are equal and in the same order
+func sameLabels(x, y []string) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// with returns the series with labelValues, fn is called with the series locked
+func (m *metric) with(labelValues []string, fn func(s *series)) {
+	if len(labelValues) != len(m.labelNames) {
+		panic(fmt.Errorf("metrics: %s expects %d label values, got %d", m.name, len(m.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	m.mx.Lock()
+	s, found := m.series[key]
+	if !found {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		if m.kind == kindHistogram {
+			s.buckets = make([]uint64, len(m.buckets))
+		}
+		m.series[key] = s
+	}
+	fn(s)
+	m.mx.Unlock()
+}
+
+// Counter a value that only goes up, ex: number of requests
+type Counter struct {
+	metric *metric
+}
+
+// Counter returns the counter name, calling Counter again with the same name returns the same counter
+func (registry *Registry) Counter(name, help string, labelNames ...string) *Counter {
+	return &Counter{metric: registry.metric(name, help, kindCounter, nil, labelNames)}
+}
+
+// Inc increments the counter by one
+func (counter *Counter) Inc(labelValues ...string) {
+	counter.Add(1, labelValues...)
+}
+
+// Add adds v to the counter, v must not be negative
+func (counter *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic(fmt.Errorf("metrics: counter %s can't decrease", counter.metric.name))
+	}
+	counter.metric.with(labelValues, func(s *series) {
+		s.value += v
+	})
+}
+
+// Gauge a value that can go up and down, ex: requests in flight
+type Gauge struct {
+	metric *metric
+}
+
+// Gauge returns the gauge name, calling Gauge again with the same name returns the same gauge
+func (registry *Registry) Gauge(name, help string, labelNames ...string) *Gauge {
+	return &Gauge{metric: registry.metric(name, help, kindGauge, nil, labelNames)}
+}
+
+// Set sets the gauge to v
+func (gauge *Gauge) Set(v float64, labelValues ...string) {
+	gauge.metric.with(labelValues, func(s *series) {
+		s.value = v
+	})
+}
+
+// Add adds v to the gauge
+func (gauge *Gauge) Add(v float64, labelValues ...string) {
+	gauge.metric.with(labelValues, func(s *series) {
+		s.value += v
+	})
+}
+
+// Inc increments the gauge by one
+func (gauge *Gauge) Inc(labelValues ...string) {
+	gauge.Add(1, labelValues...)
+}
+
+// Dec decrements the gauge by one
+func (gauge *Gauge) Dec(labelValues ...string) {
+	gauge.Add(-1, labelValues...)
+}
+
+// Histogram counts observations in buckets, ex: request latencies
+type Histogram struct {
+	metric *metric
+}
+
+// Histogram returns the histogram name, nil buckets uses DefaultBuckets, calling Histogram again with
+// the same name returns the same histogram
+func (registry *Registry) Histogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	return &Histogram{metric: registry.metric(name, help, kindHistogram, buckets, labelNames)}
+}
+
+// Observe adds the observation v
+func (histogram *Histogram) Observe(v float64, labelValues ...string) {
+	histogram.metric.with(labelValues, func(s *series) {
+		s.value += v
+		s.count++
+		if i := sort.SearchFloat64s(histogram.metric.buckets, v); i < len(s.buckets) {
+			s.buckets[i]++
+		}
+	})
+}
+
+// WriteTo writes the metrics in the prometheus text exposition format
+func (registry *Registry) WriteTo(w io.Writer) (int64, error) {
+	registry.mx.Lock()
+	metrics := make([]*metric, 0, len(registry.metrics))
+	for _, m := range registry.metrics {
+		metrics = append(metrics, m)
+	}
+	registry.mx.Unlock()
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].name < metrics[j].name })
+
+	counter := &countWriter{w: w}
+	writer := bufio.NewWriter(counter)
+	for _, m := range metrics {
+		m.write(writer)
+	}
+	err := writer.Flush()
+	return counter.n, err
+}
+
+// Handler returns a http.Handler serving the metrics to prometheus scrapers
+func (registry *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = registry.WriteTo(rw)
+	})
+}
+
+func (m *metric) write(w *bufio.Writer) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	if len(m.series) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if m.help != "" {
+		fmt.Fprintf(w, "# HELP %s %s\n", m.name, escapeHelp(m.help))
+	}
+	fmt.Fprintf(w, "# TYPE %s %s\n", m.name, m.kind)
+
+	for _, key := range keys {
+		s := m.series[key]
+		if m.kind != kindHistogram {
+			fmt.Fprintf(w, "%s%s %s\n", m.name, labels(m.labelNames, s.labelValues, ""), formatFloat(s.value))
+			continue
+		}
+
+		var cumulative uint64
+		for i, bucket := range m.buckets {
+			cumulative += s.buckets[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", m.name, labels(m.labelNames, s.labelValues, formatFloat(bucket)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", m.name, labels(m.labelNames, s.labelValues, "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", m.name, labels(m.labelNames, s.labelValues, ""), formatFloat(s.value))
+		fmt.Fprintf(w, "%s_count%s %d\n", m.name, labels(m.labelNames, s.labelValues, ""), s.count)
+	}
+}
+
+// labels formats the label pairs, le is added for histogram buckets
+func labels(names, values []string, le string) string {
+	if len(names) == 0 && le == "" {
+		return ""
+	}
+	pairs := make([]string, 0, len(names)+1)
+	for i, name := range names {
+		pairs = append(pairs, name+`="`+escapeLabel(values[i])+`"`)
+	}
+	if le != "" {
+		pairs = append(pairs, `le="`+le+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,124 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"github.com/CloudyKit/framework/app"
+	"github.com/CloudyKit/framework/request"
+	"github.com/CloudyKit/framework/tdutils"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	registry := NewRegistry()
+	registry.Counter("jobs_total", "Number of jobs.", "queue").Add(2, `mail"s`)
+	registry.Gauge("workers", "Number of\nworkers.").Set(3)
+	histogram := registry.Histogram("job_seconds", "", []float64{1, 0.5}, "queue")
+	histogram.Observe(0.5, "mail")
+	histogram.Observe(0.75, "mail")
+	histogram.Observe(2, "mail")
+
+	var output bytes.Buffer
+	if _, err := registry.WriteTo(&output); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# TYPE job_seconds histogram
+job_seconds_bucket{queue="mail",le="0.5"} 1
+job_seconds_bucket{queue="mail",le="1"} 2
+job_seconds_bucket{queue="mail",le="+Inf"} 3
+job_seconds_sum{queue="mail"} 3.25
+job_seconds_count{queue="mail"} 3
+# HELP jobs_total Number of jobs.
+# TYPE jobs_total counter
+jobs_total{queue="mail\"s"} 2
+# HELP workers Number of\nworkers.
+# TYPE workers gauge
+workers 3
+`
+	if output.String() != expected {
+		t.Errorf("unexpected exposition:\n%s\nwant:\n%s", output.String(), expected)
+	}
+}
+
+func TestRegistry_Labels(t *testing.T) {
+	registry := NewRegistry()
+	registry.Counter("jobs_total", "", "queue")
+	registry.Counter("jobs_total", "", "queue")
+
+	for _, register := range []func(){
+		func() { registry.Counter("jobs_total", "", "worker") },
+		func() { registry.Counter("jobs_total", "") },
+		func() { registry.Gauge("jobs_total", "", "queue") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("registering jobs_total with other labels or kind should panic")
+				}
+			}()
+			register()
+		}()
+	}
+}
+
+func TestComponent(t *testing.T) {
+	kernel := app.New()
+	component := &Component{}
+	kernel.Bootstrap(component)
+
+	kernel.AddHandlerName("users.show", "GET", "/users/:id", request.HandlerFunc(func(c *request.Context) {
+		c.WriteString("user")
+	}))
+	kernel.AddHandlerName("users.create", "POST", "/users", request.HandlerFunc(func(c *request.Context) {
+		panic("create failed")
+	}))
+	kernel.Dispatch("hub.test", &app.ShutdownEvent{})
+	ODMObserver{}.ObserveOperation(kernel.Registry, "users", "find_one")(errors.New("no server"))
+
+	tester := tdutils.NewHTTPTester(t, kernel)
+	tester.GetRequest("/users/1").ExpectStatus(http.StatusOK, "route should respond")
+	r, _ := http.NewRequest("POST", "/users", nil)
+	tester.Request(r).ExpectStatus(http.StatusInternalServerError, "panic should respond with an internal server error")
+
+	var output bytes.Buffer
+	component.Registry.WriteTo(&output)
+	for _, line := range []string{
+		`http_requests_total{route="users.show",method="GET",status="200"} 1`,
+		`http_requests_total{route="users.create",method="POST",status="500"} 1`,
+		`http_request_duration_seconds_count{route="users.show",method="GET"} 1`,
+		`http_requests_in_flight 0`,
+		`events_dispatched_total{event="hub.test",result="ok"} 1`,
+		`odm_operation_duration_seconds_count{collection="users",operation="find_one",status="error"} 1`,
+	} {
+		if !strings.Contains(output.String(), line+"\n") {
+			t.Errorf("missing %s in:\n%s", line, output.String())
+		}
+	}
+
+	tester.GetRequest("/metrics").ExpectOutput(output.String(), "metrics endpoint should serve the exposition")
+}
@@ -37,7 +37,7 @@ func (filter *Filter) Set(k string, v interface{}) *Filter {
 
 // ByID query by object _id
 func ByID(id interface{}) bson.D {
-	return primitive.D{{"_id", id}}
+	return primitive.D{{Key: "_id", Value: id}}
 }
 
 // lt less
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"github.com/CloudyKit/framework/app"
 	"github.com/CloudyKit/framework/container"
+	"github.com/CloudyKit/framework/health"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"reflect"
+	"sync"
 )
 
 var ClientType = reflect.TypeOf((*mongo.Client)(nil))
@@ -53,26 +56,74 @@ type Component struct {
 	Database      string
 	ClientOptions []*options.ClientOptions
 	Client        *mongo.Client
+	// HealthCheck names the readiness check pinging the mongo primary, the check is not registered
+	// when empty, ex: "mongo", see health.Checks
+	HealthCheck string
+	// Observers observers notified of the operations of the managers, ex: metrics.ODMObserver, see Observer
+	Observers Observers
+
+	// mx guards Client and connected, the client is connected by the providers, the
+	// health check and disconnected by Stop from different goroutines
+	mx        sync.Mutex
+	connected bool
 }
 
+// client returns the component client, connecting a new client case Client is nil
 func (component *Component) client() (*mongo.Client, error) {
-	return mongo.Connect(context.TODO(), component.ClientOptions...)
+	component.mx.Lock()
+	defer component.mx.Unlock()
+	if component.Client == nil {
+		client, err := mongo.Connect(context.TODO(), component.ClientOptions...)
+		if err != nil {
+			return nil, err
+		}
+		component.Client = client
+		component.connected = true
+	}
+	return component.Client, nil
 }
 
 func (component *Component) Bootstrap(app *app.Kernel) {
 	app.Registry.WithTypeAndProviderFunc(ClientType, func(registry *container.Registry) interface{} {
-		if component.Client == nil {
-			var err error
-			component.Client, err = component.client()
-			if err != nil {
-				panic(err)
-			}
+		client, err := component.client()
+		if err != nil {
+			panic(err)
 		}
-		return component.Client
+		return client
 	})
 	app.Registry.WithTypeAndProviderFunc(DatabaseType, func(registry *container.Registry) interface{} {
 		return CurrentClient(registry).Database(component.Database)
 	})
+
+	if len(component.Observers) > 0 {
+		app.Registry.WithTypeAndValue(ObserversType, component.Observers)
+	}
+
+	if component.HealthCheck != "" {
+		health.GetChecks(app.Registry).Add(component.HealthCheck, component)
+	}
+}
+
+// Check pings the mongo primary, see health.Checker
+func (component *Component) Check(ctx context.Context) error {
+	client, err := component.client()
+	if err != nil {
+		return err
+	}
+	return client.Ping(ctx, readpref.Primary())
+}
+
+// Stop disconnects the client connected by the component, the client is released so that
+// a restarted kernel connects a new one, see app.Stopper
+func (component *Component) Stop(ctx context.Context) error {
+	component.mx.Lock()
+	defer component.mx.Unlock()
+	if !component.connected {
+		return nil
+	}
+	client := component.Client
+	component.Client, component.connected = nil, false
+	return client.Disconnect(ctx)
 }
 
 func NewComponent(databaseName string, options ...*options.ClientOptions) *Component {
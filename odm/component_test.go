@@ -0,0 +1,56 @@
+package odm
+
+import (
+	"context"
+	"github.com/CloudyKit/framework/app"
+	"github.com/CloudyKit/framework/health"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"sync"
+	"testing"
+)
+
+func TestComponentStop(t *testing.T) {
+	kernel := app.New()
+	component := NewComponent("test", options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	kernel.Bootstrap(component)
+	defer component.Stop(context.Background())
+
+	for cycle := 0; cycle < 2; cycle++ {
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if CurrentClient(kernel.Registry) == nil {
+					t.Error("expected a client")
+				}
+			}()
+		}
+		wg.Wait()
+
+		client := CurrentClient(kernel.Registry)
+		if err := component.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if err := component.Stop(context.Background()); err != nil {
+			t.Fatalf("stopping twice should do nothing, got %v", err)
+		}
+		if CurrentClient(kernel.Registry) == client {
+			t.Errorf("cycle %d: a new client should be connected after Stop", cycle)
+		}
+	}
+}
+
+func TestComponentHealthCheck(t *testing.T) {
+	kernel := app.New()
+	kernel.Bootstrap(NewComponent("test"))
+	if names := health.GetChecks(kernel.Registry).Names(); len(names) != 0 {
+		t.Errorf("the mongo check should be opt-in, got %v", names)
+	}
+
+	kernel = app.New()
+	kernel.Bootstrap(&Component{Database: "test", HealthCheck: "mongo"})
+	if names := health.GetChecks(kernel.Registry).Names(); len(names) != 1 || names[0] != "mongo" {
+		t.Errorf("expected the mongo check got %v", names)
+	}
+}
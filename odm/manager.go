@@ -20,7 +20,10 @@ type Manager struct {
 }
 
 func (m *Manager) BulkWrite(models []mongo.WriteModel, opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) {
-	return m.Collection.BulkWrite(m.Context, models, opts...)
+	done := m.observe("bulk_write")
+	result, err := m.Collection.BulkWrite(m.Context, models, opts...)
+	done(err)
+	return result, err
 }
 
 func (m *Manager) InsertOne(document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
@@ -29,7 +32,10 @@ func (m *Manager) InsertOne(document interface{}, opts ...*options.InsertOneOpti
 	if err != nil {
 		return nil, err
 	}
-	return m.Collection.InsertOne(m.Context, payload.Document, payload.Options...)
+	done := m.observe("insert_one")
+	result, err := m.Collection.InsertOne(m.Context, payload.Document, payload.Options...)
+	done(err)
+	return result, err
 }
 
 func (m *Manager) InsertMany(documents []interface{}, opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error) {
@@ -38,7 +44,10 @@ func (m *Manager) InsertMany(documents []interface{}, opts ...*options.InsertMan
 	if err != nil {
 		return nil, err
 	}
-	return m.Collection.InsertMany(m.Context, payload.Documents, payload.Options...)
+	done := m.observe("insert_many")
+	result, err := m.Collection.InsertMany(m.Context, payload.Documents, payload.Options...)
+	done(err)
+	return result, err
 }
 
 func (m *Manager) filterEvent(filter interface{}) interface{} {
@@ -58,7 +67,10 @@ func (m *Manager) DeleteOne(filter interface{}, opts ...*options.DeleteOptions)
 		return nil, err
 	}
 
-	return m.Collection.DeleteOne(m.Context, payload.Filter, payload.Options...)
+	done := m.observe("delete_one")
+	result, err := m.Collection.DeleteOne(m.Context, payload.Filter, payload.Options...)
+	done(err)
+	return result, err
 }
 
 func (m *Manager) DeleteMany(filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
@@ -67,11 +79,14 @@ func (m *Manager) DeleteMany(filter interface{}, opts ...*options.DeleteOptions)
 	if err != nil {
 		return nil, err
 	}
-	return m.Collection.DeleteMany(m.Context, filter, opts...)
+	done := m.observe("delete_many")
+	result, err := m.Collection.DeleteMany(m.Context, payload.Filter, payload.Options...)
+	done(err)
+	return result, err
 }
 
 func (m *Manager) UpdateByID(id interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
-	return m.UpdateOne(primitive.D{{"_id", id}}, update, opts...)
+	return m.UpdateOne(primitive.D{{Key: "_id", Value: id}}, update, opts...)
 }
 
 func (m *Manager) UpdateOne(filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
@@ -80,7 +95,10 @@ func (m *Manager) UpdateOne(filter interface{}, update interface{}, opts ...*opt
 	if err != nil {
 		return nil, err
 	}
-	return m.Collection.UpdateOne(m.Context, payload.Filter, payload.Document, payload.Options...)
+	done := m.observe("update_one")
+	result, err := m.Collection.UpdateOne(m.Context, payload.Filter, payload.Document, payload.Options...)
+	done(err)
+	return result, err
 }
 
 func (m *Manager) UpdateMany(filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
@@ -89,7 +107,10 @@ func (m *Manager) UpdateMany(filter interface{}, update interface{}, opts ...*op
 	if err != nil {
 		return nil, err
 	}
-	return m.Collection.UpdateMany(m.Context, payload.Filter, payload.Document, payload.Options...)
+	done := m.observe("update_many")
+	result, err := m.Collection.UpdateMany(m.Context, payload.Filter, payload.Document, payload.Options...)
+	done(err)
+	return result, err
 }
 
 func (m *Manager) ReplaceOne(filter interface{}, replacement interface{}, opts ...*options.ReplaceOptions) (*mongo.UpdateResult, error) {
@@ -98,11 +119,17 @@ func (m *Manager) ReplaceOne(filter interface{}, replacement interface{}, opts .
 	if err != nil {
 		return nil, err
 	}
-	return m.Collection.ReplaceOne(m.Context, payload.Filter, payload.Document, payload.Options...)
+	done := m.observe("replace_one")
+	result, err := m.Collection.ReplaceOne(m.Context, payload.Filter, payload.Document, payload.Options...)
+	done(err)
+	return result, err
 }
 
 func (m *Manager) Aggregate(pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
-	return m.Collection.Aggregate(m.Context, pipeline, opts...)
+	done := m.observe("aggregate")
+	result, err := m.Collection.Aggregate(m.Context, pipeline, opts...)
+	done(err)
+	return result, err
 }
 
 func (m *Manager) CountDocuments(filter interface{}, opts ...*options.CountOptions) (int64, error) {
@@ -111,11 +138,17 @@ func (m *Manager) CountDocuments(filter interface{}, opts ...*options.CountOptio
 	if err != nil {
 		return 0, err
 	}
-	return m.Collection.CountDocuments(m.Context, payload.Filter, payload.Options...)
+	done := m.observe("count_documents")
+	result, err := m.Collection.CountDocuments(m.Context, payload.Filter, payload.Options...)
+	done(err)
+	return result, err
 }
 
 func (m *Manager) EstimatedDocumentCount(opts ...*options.EstimatedDocumentCountOptions) (int64, error) {
-	return m.Collection.EstimatedDocumentCount(m.Context, opts...)
+	done := m.observe("estimated_document_count")
+	result, err := m.Collection.EstimatedDocumentCount(m.Context, opts...)
+	done(err)
+	return result, err
 }
 
 func (m *Manager) Distinct(fieldName string, filter interface{}, opts ...*options.DistinctOptions) ([]interface{}, error) {
@@ -124,7 +157,10 @@ func (m *Manager) Distinct(fieldName string, filter interface{}, opts ...*option
 	if err != nil {
 		return nil, err
 	}
-	return m.Collection.Distinct(m.Context, payload.FieldName, payload.Filter, payload.Options...)
+	done := m.observe("distinct")
+	result, err := m.Collection.Distinct(m.Context, payload.FieldName, payload.Filter, payload.Options...)
+	done(err)
+	return result, err
 }
 
 func (m *Manager) Find(filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
@@ -133,7 +169,10 @@ func (m *Manager) Find(filter interface{}, opts ...*options.FindOptions) (*mongo
 	if err != nil {
 		return nil, err
 	}
-	return m.Collection.Find(m.Context, payload.Filter, payload.Options...)
+	done := m.observe("find")
+	result, err := m.Collection.Find(m.Context, payload.Filter, payload.Options...)
+	done(err)
+	return result, err
 }
 
 func (m *Manager) FindOne(filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
@@ -142,7 +181,10 @@ func (m *Manager) FindOne(filter interface{}, opts ...*options.FindOneOptions) *
 	if err != nil {
 		return nil
 	}
-	return m.Collection.FindOne(m.Context, payload.Filter, opts...)
+	done := m.observe("find_one")
+	result := m.Collection.FindOne(m.Context, payload.Filter, payload.Options...)
+	done(result.Err())
+	return result
 }
 
 func (m *Manager) FindOneAndDelete(filter interface{}, opts ...*options.FindOneAndDeleteOptions) *mongo.SingleResult {
@@ -151,7 +193,10 @@ func (m *Manager) FindOneAndDelete(filter interface{}, opts ...*options.FindOneA
 	if err != nil {
 		return nil
 	}
-	return m.Collection.FindOneAndDelete(m.Context, payload.Filter, payload.Options...)
+	done := m.observe("find_one_and_delete")
+	result := m.Collection.FindOneAndDelete(m.Context, payload.Filter, payload.Options...)
+	done(result.Err())
+	return result
 }
 
 func (m *Manager) FindOneAndReplace(filter interface{}, replacement interface{}, opts ...*options.FindOneAndReplaceOptions) *mongo.SingleResult {
@@ -160,7 +205,10 @@ func (m *Manager) FindOneAndReplace(filter interface{}, replacement interface{},
 	if err != nil {
 		return nil
 	}
-	return m.Collection.FindOneAndReplace(m.Context, payload.Filter, payload.Document, payload.Options...)
+	done := m.observe("find_one_and_replace")
+	result := m.Collection.FindOneAndReplace(m.Context, payload.Filter, payload.Document, payload.Options...)
+	done(result.Err())
+	return result
 }
 
 func (m *Manager) FindOneAndUpdate(filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
@@ -169,7 +217,10 @@ func (m *Manager) FindOneAndUpdate(filter interface{}, update interface{}, opts
 	if err != nil {
 		return nil
 	}
-	return m.Collection.FindOneAndUpdate(m.Context, payload.Filter, payload.Document, payload.Options...)
+	done := m.observe("find_one_and_update")
+	result := m.Collection.FindOneAndUpdate(m.Context, payload.Filter, payload.Document, payload.Options...)
+	done(result.Err())
+	return result
 }
 
 func (m *Manager) FindOneByID(id interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
@@ -0,0 +1,76 @@
+package odm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/CloudyKit/framework/container"
+	"github.com/CloudyKit/framework/event"
+	"github.com/CloudyKit/framework/odm/events"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"testing"
+)
+
+// TestManagerPayload checks the operations use the filter and the options of the event payload, the
+// client is not connected, the driver validates the filter and the options before reaching the server
+func TestManagerPayload(t *testing.T) {
+	client, err := mongo.NewClient(options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher := event.NewDispatcher()
+	dispatcher.Subscribe(events.DeleteManyKey, func(e *events.DeleteEvent) {
+		e.Filter = nil
+	})
+	dispatcher.Subscribe(events.FindOneKey, func(e *events.FindOneEvent) {
+		e.Options = []*options.FindOneOptions{options.FindOne().SetSort(make(chan int))}
+	})
+
+	registry := container.New()
+	defer registry.Dispose()
+	registry.WithValues(dispatcher)
+
+	manager := &Manager{
+		Context:    context.Background(),
+		Collection: client.Database("test").Collection("users"),
+		Registry:   registry,
+	}
+
+	if _, err := manager.DeleteMany(map[string]interface{}{"name": "john"}); !errors.Is(err, mongo.ErrNilDocument) {
+		t.Errorf("DeleteMany should use the payload filter, got %v", err)
+	}
+	if err := manager.FindOne(map[string]interface{}{"name": "john"}).Err(); err == nil || errors.Is(err, mongo.ErrClientDisconnected) {
+		t.Errorf("FindOne should use the payload options, got %v", err)
+	}
+}
+
+type operationObserver []string
+
+func (observer *operationObserver) ObserveOperation(_ *container.Registry, collection, operation string) func(err error) {
+	return func(err error) {
+		*observer = append(*observer, collection+" "+operation+" "+fmt.Sprint(err != nil))
+	}
+}
+
+func TestManagerObservers(t *testing.T) {
+	client, err := mongo.NewClient(options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	observer := &operationObserver{}
+	registry := container.New()
+	defer registry.Dispose()
+	registry.WithValues(event.NewDispatcher())
+	registry.WithTypeAndValue(ObserversType, Observers{observer})
+
+	manager := &Manager{Context: context.Background(), Collection: client.Database("test").Collection("users"), Registry: registry}
+	manager.DeleteOne(nil)
+	manager.FindOne(map[string]interface{}{}, options.FindOne().SetSort(make(chan int)))
+
+	if got := fmt.Sprint(*observer); got != "[users delete_one true users find_one true]" {
+		t.Errorf("unexpected observed operations %s", got)
+	}
+}
@@ -0,0 +1,119 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"github.com/CloudyKit/framework/app"
+	"github.com/CloudyKit/framework/console"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// Component registers the migrator in the app and adds the migrate console command, see command
+type Component struct {
+	Migrator *Migrator // Migrator defaults to GetMigrator
+	Command  string    // Command name of the console command, defaults to "migrate", "-" disables the command
+	AutoUp   bool      // AutoUp applies the pending migrations when the app starts, see app.Starter
+}
+
+func (component *Component) Bootstrap(a *app.Kernel) {
+	if component.Migrator == nil {
+		component.Migrator = GetMigrator(a.Registry)
+	} else {
+		if component.Migrator.Registry == nil {
+			component.Migrator.Registry = a.Registry
+		}
+		a.Registry.WithTypeAndValue(MigratorType, component.Migrator)
+	}
+
+	switch component.Command {
+	case "-":
+	case "":
+		component.Command = "migrate"
+		fallthrough
+	default:
+		a.AddCommand(component.Command, "Shows, applies or reverts the database migrations\n"+
+			"The action status lists the migrations, up applies the pending migrations and down reverts the last migration", &command{})
+	}
+}
+
+// Start applies the pending migrations when AutoUp is set
+func (component *Component) Start(ctx context.Context) error {
+	if !component.AutoUp {
+		return nil
+	}
+	_, err := component.Migrator.Up(ctx, Options{})
+	return err
+}
+
+// command the migrate console command
+type command struct {
+	Migrator *Migrator
+
+	Action string `arg:"action" default:"status" usage:"status, up or down"`
+	To     int64  `flag:"to" usage:"version to migrate to, up applies the versions up to it and down reverts the versions after it"`
+	Steps  int    `flag:"steps" usage:"max number of migrations to run, down defaults to 1"`
+	DryRun bool   `flag:"dry-run" usage:"lists the migrations that would run"`
+
+	out io.Writer
+}
+
+func (command *command) Run(ctx context.Context) error {
+	out := command.out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	options := Options{To: command.To, Steps: command.Steps, DryRun: command.DryRun}
+	var (
+		migrations []*Migration
+		err        error
+		verb       string
+	)
+	switch command.Action {
+	case "status":
+		return command.status(ctx, out)
+	case "up":
+		verb = "applied"
+		migrations, err = command.Migrator.Up(ctx, options)
+	case "down":
+		verb = "reverted"
+		migrations, err = command.Migrator.Down(ctx, options)
+	default:
+		return console.Exit(console.ExitUsage, fmt.Errorf("unknown action %q, expected status, up or down", command.Action))
+	}
+
+	if command.DryRun {
+		verb = "would be " + verb
+	}
+	for _, migration := range migrations {
+		fmt.Fprintf(out, "%s %d %s\n", verb, migration.Version, migration.Name)
+	}
+	if len(migrations) == 0 && err == nil {
+		fmt.Fprintln(out, "no migrations to run")
+	}
+	return err
+}
+
+func (command *command) status(ctx context.Context, out io.Writer) error {
+	statuses, err := command.Migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+	for _, status := range statuses {
+		state := "pending"
+		if status.Applied {
+			state = "applied " + status.AppliedAt.Local().Format(time.RFC3339)
+		}
+		if status.Migration == nil {
+			state += " (not registered)"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, state)
+	}
+	return w.Flush()
+}
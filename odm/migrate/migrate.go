@@ -0,0 +1,319 @@
+package migrate
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/CloudyKit/framework/container"
+	"github.com/CloudyKit/framework/odm"
+	"github.com/CloudyKit/framework/request"
+	"go.mongodb.org/mongo-driver/mongo"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCollection collection storing the applied migrations, the lock is stored in DefaultCollection+"_lock"
+	DefaultCollection = "migrations"
+	// DefaultLockTTL time after which the lock of a crashed runner is taken over
+	DefaultLockTTL = 10 * time.Minute
+)
+
+var MigratorType = reflect.TypeOf((*Migrator)(nil))
+
+// GetMigrator returns the migrator registered in the registry, case there's no migrator in the registry
+// a new one is registered, the migrator uses the database provided by the registry, see odm.Component
+func GetMigrator(registry *container.Registry) *Migrator {
+	if migrator, _ := registry.LoadType(MigratorType).(*Migrator); migrator != nil {
+		return migrator
+	}
+	migrator := &Migrator{Registry: registry}
+	registry.WithTypeAndValue(MigratorType, migrator)
+	return migrator
+}
+
+var (
+	ErrLocked       = errors.New("migrate: migrations locked by another runner")
+	ErrIrreversible = errors.New("migrate: migration has no down function")
+	ErrUnknown      = errors.New("migrate: applied migration is not registered")
+)
+
+// DB the database given to the migration functions
+type DB struct {
+	*mongo.Database
+	Registry *container.Registry
+}
+
+// Manager returns an odm.Manager of the collection name running with ctx
+func (db *DB) Manager(ctx context.Context, name string) *odm.Manager {
+	return &odm.Manager{Context: ctx, Collection: db.Collection(name), Registry: db.Registry}
+}
+
+// Func applies or reverts a migration
+type Func func(ctx context.Context, db *DB) error
+
+// Migration a versioned change of the database, migrations are applied in the order of their versions,
+// ex: 20240131120000 for a migration written at 2024-01-31 12:00:00
+type Migration struct {
+	Version int64
+	Name    string
+	Up      Func
+	Down    Func // Down reverts Up, migrations without Down can't be reverted
+}
+
+// Record an applied migration
+type Record struct {
+	Version   int64     `bson:"_id"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+// Status the state of a migration, migrations applied but no longer registered have a nil Migration
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Migration *Migration
+}
+
+// Options limits the migrations run by Migrator.Up and Migrator.Down
+type Options struct {
+	To     int64 // To Up applies the versions up to To, Down reverts the versions after To
+	Steps  int   // Steps max number of migrations run, Down defaults to 1 when To is not set
+	DryRun bool  // DryRun returns the migrations that would run without running them
+}
+
+// Migrator runs the registered migrations, the applied versions are recorded in the store and the
+// store is locked while the migrations run, so two instances never run migrations concurrently
+type Migrator struct {
+	Database *mongo.Database     // Database defaults to the database provided by Registry
+	Registry *container.Registry // Registry given to the migrations and used for logging
+	Store    Store               // Store defaults to NewStore(Database, DefaultCollection)
+	LockTTL  time.Duration       // LockTTL defaults to DefaultLockTTL, the lock is renewed before each migration, so a single migration must finish within LockTTL
+	Owner    string              // Owner identifies the runner holding the lock, defaults to hostname, pid and a random suffix
+
+	mx         sync.Mutex
+	migrations map[int64]*Migration
+}
+
+// New creates a migrator of the database
+func New(database *mongo.Database) *Migrator {
+	return &Migrator{Database: database}
+}
+
+// Add registers a migration, see Migrator.Register
+func (migrator *Migrator) Add(version int64, name string, up, down Func) {
+	migrator.Register(&Migration{Version: version, Name: name, Up: up, Down: down})
+}
+
+// Register registers the migrations, Register panics when a version is registered twice or a
+// migration has no Up function
+func (migrator *Migrator) Register(migrations ...*Migration) {
+	migrator.mx.Lock()
+	defer migrator.mx.Unlock()
+	if migrator.migrations == nil {
+		migrator.migrations = map[int64]*Migration{}
+	}
+	for _, migration := range migrations {
+		if migration.Up == nil {
+			panic(fmt.Sprintf("migrate: migration %d %s has no up function", migration.Version, migration.Name))
+		}
+		if registered, found := migrator.migrations[migration.Version]; found {
+			panic(fmt.Sprintf("migrate: version %d of %s is taken by %s", migration.Version, migration.Name, registered.Name))
+		}
+		migrator.migrations[migration.Version] = migration
+	}
+}
+
+// Migrations returns the registered migrations sorted by version
+func (migrator *Migrator) Migrations() []*Migration {
+	migrator.mx.Lock()
+	defer migrator.mx.Unlock()
+	migrations := make([]*Migration, 0, len(migrator.migrations))
+	for _, migration := range migrator.migrations {
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations
+}
+
+func (migrator *Migrator) database() *mongo.Database {
+	if migrator.Database == nil && migrator.Registry != nil {
+		database, _ := migrator.Registry.LoadType(odm.DatabaseType).(*mongo.Database)
+		return database
+	}
+	return migrator.Database
+}
+
+func (migrator *Migrator) store() (Store, error) {
+	if migrator.Store != nil {
+		return migrator.Store, nil
+	}
+	database := migrator.database()
+	if database == nil {
+		return nil, errors.New("migrate: no database, set Migrator.Database or bootstrap odm.Component")
+	}
+	return NewStore(database, DefaultCollection), nil
+}
+
+// Status returns the status of the registered and applied migrations sorted by version
+func (migrator *Migrator) Status(ctx context.Context) ([]Status, error) {
+	store, err := migrator.store()
+	if err != nil {
+		return nil, err
+	}
+	records, err := store.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]Record, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	var statuses []Status
+	for _, migration := range migrator.Migrations() {
+		record, found := applied[migration.Version]
+		delete(applied, migration.Version)
+		statuses = append(statuses, Status{Version: migration.Version, Name: migration.Name, Applied: found, AppliedAt: record.AppliedAt, Migration: migration})
+	}
+	for _, record := range applied {
+		statuses = append(statuses, Status{Version: record.Version, Name: record.Name, Applied: true, AppliedAt: record.AppliedAt})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+// Up applies the pending migrations in version order, the migrations applied are returned,
+// a failing migration stops the run and is not recorded
+func (migrator *Migrator) Up(ctx context.Context, options Options) ([]*Migration, error) {
+	return migrator.run(ctx, options, true)
+}
+
+// Down reverts the applied migrations in the reverse version order, the migrations reverted are returned
+func (migrator *Migrator) Down(ctx context.Context, options Options) ([]*Migration, error) {
+	if options.To == 0 && options.Steps == 0 {
+		options.Steps = 1
+	}
+	return migrator.run(ctx, options, false)
+}
+
+func (migrator *Migrator) run(ctx context.Context, options Options, up bool) (_ []*Migration, err error) {
+	store, err := migrator.store()
+	if err != nil {
+		return nil, err
+	}
+	owner, ttl := migrator.owner(), migrator.LockTTL
+	if ttl <= 0 {
+		ttl = DefaultLockTTL
+	}
+	if !options.DryRun {
+		if err := store.Lock(ctx, owner, ttl); err != nil {
+			return nil, err
+		}
+		defer func() {
+			err = errors.Join(err, store.Unlock(context.WithoutCancel(ctx), owner))
+		}()
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := plan(statuses, options, up)
+	if err != nil || options.DryRun {
+		return plan, err
+	}
+
+	db := &DB{Database: migrator.database(), Registry: migrator.Registry}
+	logger := request.GetLogger(migrator.Registry)
+	for i, migration := range plan {
+		if err := ctx.Err(); err != nil {
+			return plan[:i], err
+		}
+		// renews the lock, the run stops with ErrLocked when another runner took over the expired lock
+		if i > 0 {
+			if err := store.Lock(ctx, owner, ttl); err != nil {
+				return plan[:i], err
+			}
+		}
+
+		direction := "up"
+		if !up {
+			direction = "down"
+		}
+		start := time.Now()
+		if up {
+			err = migration.Up(ctx, db)
+			if err == nil {
+				err = store.Record(ctx, Record{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()})
+			}
+		} else {
+			err = migration.Down(ctx, db)
+			if err == nil {
+				err = store.Remove(ctx, migration.Version)
+			}
+		}
+		if err != nil {
+			return plan[:i], fmt.Errorf("migrate: %s %d %s: %w", direction, migration.Version, migration.Name, err)
+		}
+		logger.Info("migrate: migration "+direction, "version", migration.Version, "name", migration.Name, "duration", time.Since(start))
+	}
+	return plan, nil
+}
+
+// plan selects the migrations to run from statuses sorted by version
+func plan(statuses []Status, options Options, up bool) ([]*Migration, error) {
+	var migrations []*Migration
+	if up {
+		for _, status := range statuses {
+			if status.Applied || options.To > 0 && status.Version > options.To {
+				continue
+			}
+			migrations = append(migrations, status.Migration)
+		}
+	} else {
+		for i := len(statuses) - 1; i >= 0; i-- {
+			status := statuses[i]
+			if !status.Applied || status.Version <= options.To {
+				continue
+			}
+			if status.Migration == nil {
+				return nil, fmt.Errorf("%w: %d %s", ErrUnknown, status.Version, status.Name)
+			}
+			if status.Migration.Down == nil {
+				return nil, fmt.Errorf("%w: %d %s", ErrIrreversible, status.Version, status.Name)
+			}
+			migrations = append(migrations, status.Migration)
+			if options.Steps > 0 && len(migrations) == options.Steps {
+				break
+			}
+		}
+	}
+
+	if options.Steps > 0 && len(migrations) > options.Steps {
+		migrations = migrations[:options.Steps]
+	}
+	return migrations, nil
+}
+
+func (migrator *Migrator) owner() string {
+	if migrator.Owner != "" {
+		return migrator.Owner
+	}
+	hostname, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("%s:%d:%s", hostname, os.Getpid(), hex.EncodeToString(suffix))
+}
@@ -0,0 +1,222 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/CloudyKit/framework/app"
+	"github.com/CloudyKit/framework/console"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memoryStore struct {
+	mx        sync.Mutex
+	records   map[int64]Record
+	owner     string
+	expiresAt time.Time
+}
+
+func (store *memoryStore) Applied(ctx context.Context) ([]Record, error) {
+	store.mx.Lock()
+	defer store.mx.Unlock()
+	var records []Record
+	for _, record := range store.records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Version < records[j].Version })
+	return records, nil
+}
+
+func (store *memoryStore) Record(ctx context.Context, record Record) error {
+	store.mx.Lock()
+	defer store.mx.Unlock()
+	if store.records == nil {
+		store.records = map[int64]Record{}
+	}
+	store.records[record.Version] = record
+	return nil
+}
+
+func (store *memoryStore) Remove(ctx context.Context, version int64) error {
+	store.mx.Lock()
+	defer store.mx.Unlock()
+	delete(store.records, version)
+	return nil
+}
+
+func (store *memoryStore) Lock(ctx context.Context, owner string, ttl time.Duration) error {
+	store.mx.Lock()
+	defer store.mx.Unlock()
+	if store.owner != "" && store.owner != owner && time.Now().Before(store.expiresAt) {
+		return ErrLocked
+	}
+	store.owner, store.expiresAt = owner, time.Now().Add(ttl)
+	return nil
+}
+
+func (store *memoryStore) Unlock(ctx context.Context, owner string) error {
+	store.mx.Lock()
+	defer store.mx.Unlock()
+	if store.owner == owner {
+		store.owner = ""
+	}
+	return nil
+}
+
+func newMigrator(log *[]string) *Migrator {
+	migrator := &Migrator{Store: &memoryStore{}}
+	step := func(name string) Func {
+		return func(ctx context.Context, db *DB) error {
+			*log = append(*log, name)
+			return nil
+		}
+	}
+	migrator.Add(3, "backfill", step("up 3"), nil)
+	migrator.Add(1, "users index", step("up 1"), step("down 1"))
+	migrator.Add(2, "rename field", step("up 2"), step("down 2"))
+	return migrator
+}
+
+func versions(migrations []*Migration) (versions []int64) {
+	for _, migration := range migrations {
+		versions = append(versions, migration.Version)
+	}
+	return
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	var log []string
+	migrator := newMigrator(&log)
+
+	migrations, err := migrator.Up(ctx, Options{To: 2, DryRun: true})
+	if err != nil || !reflect.DeepEqual(versions(migrations), []int64{1, 2}) || len(log) != 0 {
+		t.Fatalf("unexpected dry run %v %v %v", versions(migrations), log, err)
+	}
+
+	migrations, err = migrator.Up(ctx, Options{})
+	if err != nil || !reflect.DeepEqual(versions(migrations), []int64{1, 2, 3}) {
+		t.Fatalf("unexpected up %v %v", versions(migrations), err)
+	}
+	if expected := []string{"up 1", "up 2", "up 3"}; !reflect.DeepEqual(log, expected) {
+		t.Errorf("expected %v, got %v", expected, log)
+	}
+
+	if migrations, err := migrator.Up(ctx, Options{}); err != nil || len(migrations) != 0 {
+		t.Errorf("expected no pending migrations, got %v %v", versions(migrations), err)
+	}
+
+	// migration 3 has no down function
+	if _, err := migrator.Down(ctx, Options{}); !errors.Is(err, ErrIrreversible) {
+		t.Errorf("expected ErrIrreversible, got %v", err)
+	}
+
+	migrator.Store.Remove(ctx, 3)
+	log = nil
+	migrations, err = migrator.Down(ctx, Options{To: 0, Steps: 2})
+	if err != nil || !reflect.DeepEqual(log, []string{"down 2", "down 1"}) {
+		t.Errorf("unexpected down %v %v", log, err)
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range statuses {
+		if status.Applied {
+			t.Errorf("migration %d should be pending", status.Version)
+		}
+	}
+}
+
+func TestMigrator_FailureAndLock(t *testing.T) {
+	ctx := context.Background()
+	var log []string
+	migrator := newMigrator(&log)
+	migrator.Add(4, "broken", func(ctx context.Context, db *DB) error {
+		return errors.New("boom")
+	}, nil)
+	migrator.Add(5, "after broken", func(ctx context.Context, db *DB) error {
+		log = append(log, "up 5")
+		return nil
+	}, nil)
+
+	migrations, err := migrator.Up(ctx, Options{})
+	if err == nil || !strings.Contains(err.Error(), "up 4 broken: boom") {
+		t.Errorf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(versions(migrations), []int64{1, 2, 3}) {
+		t.Errorf("unexpected applied migrations %v", versions(migrations))
+	}
+
+	statuses, _ := migrator.Status(ctx)
+	if statuses[3].Applied || statuses[4].Applied {
+		t.Error("migrations after the failure should be pending")
+	}
+
+	other := &Migrator{Store: migrator.Store, Owner: "other"}
+	if err := other.Store.Lock(ctx, other.Owner, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx, Options{}); !errors.Is(err, ErrLocked) {
+		t.Errorf("expected ErrLocked, got %v", err)
+	}
+}
+
+func TestMigrator_LockRenewal(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryStore{}
+	migrator := &Migrator{Store: store, Owner: "runner", LockTTL: time.Millisecond}
+
+	var log []string
+	migrator.Add(1, "slow", func(ctx context.Context, db *DB) error {
+		log = append(log, "up 1")
+		// the lock expires during the migration and is taken over by another runner
+		time.Sleep(5 * time.Millisecond)
+		return store.Lock(ctx, "other", time.Minute)
+	}, nil)
+	migrator.Add(2, "next", func(ctx context.Context, db *DB) error {
+		log = append(log, "up 2")
+		return nil
+	}, nil)
+
+	migrations, err := migrator.Up(ctx, Options{})
+	if !errors.Is(err, ErrLocked) || !reflect.DeepEqual(versions(migrations), []int64{1}) || !reflect.DeepEqual(log, []string{"up 1"}) {
+		t.Errorf("the run should stop when the lock is lost, got %v %v %v", versions(migrations), log, err)
+	}
+}
+
+func TestComponent(t *testing.T) {
+	var log []string
+	var out bytes.Buffer
+	migrator := newMigrator(&log)
+	// RunCLI disposes the kernel, each run uses a new kernel sharing the migrator
+	newKernel := func() *app.Kernel {
+		kernel := app.New()
+		kernel.Bootstrap(&Component{Migrator: migrator})
+		definition, _ := console.GetCommands(kernel.Registry).Lookup("migrate")
+		definition.Command.(*command).out = &out
+		return kernel
+	}
+
+	if code := newKernel().RunCLI([]string{"app", "migrate", "up", "-steps", "2"}); code != console.ExitOK {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	if expected := "applied 1 users index\napplied 2 rename field\n"; out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+
+	out.Reset()
+	if code := newKernel().RunCLI([]string{"app", "migrate"}); code != console.ExitOK {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[1], "applied") || !strings.HasSuffix(lines[3], "pending") {
+		t.Errorf("unexpected status output %q", out.String())
+	}
+}
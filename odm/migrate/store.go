@@ -0,0 +1,75 @@
+package migrate
+
+import (
+	"context"
+	"github.com/CloudyKit/framework/odm/bsoner"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// Store records the applied migrations and holds the lock of the runner
+type Store interface {
+	Applied(ctx context.Context) ([]Record, error)
+	Record(ctx context.Context, record Record) error
+	Remove(ctx context.Context, version int64) error
+	// Lock takes or renews the lock for owner, ErrLocked is returned while another owner holds a lock not expired
+	Lock(ctx context.Context, owner string, ttl time.Duration) error
+	Unlock(ctx context.Context, owner string) error
+}
+
+const lockID = "lock"
+
+// mongoStore stores the records in a collection and the lock in the collection with the "_lock" suffix
+type mongoStore struct {
+	records *mongo.Collection
+	locks   *mongo.Collection
+}
+
+// NewStore creates a store of the collection name of database
+func NewStore(database *mongo.Database, name string) Store {
+	return &mongoStore{records: database.Collection(name), locks: database.Collection(name + "_lock")}
+}
+
+func (store *mongoStore) Applied(ctx context.Context) ([]Record, error) {
+	cursor, err := store.records.Find(ctx, primitive.D{}, options.Find().SetSort(primitive.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	err = cursor.All(ctx, &records)
+	return records, err
+}
+
+func (store *mongoStore) Record(ctx context.Context, record Record) error {
+	_, err := store.records.InsertOne(ctx, record)
+	return err
+}
+
+func (store *mongoStore) Remove(ctx context.Context, version int64) error {
+	_, err := store.records.DeleteOne(ctx, bsoner.ByID(version))
+	return err
+}
+
+func (store *mongoStore) Lock(ctx context.Context, owner string, ttl time.Duration) error {
+	now := time.Now()
+	// a lock held by another owner doesn't match the filter, so the upsert fails with a duplicate key
+	_, err := store.locks.UpdateOne(ctx,
+		bsoner.FilterBy("_id", lockID).Or(
+			bsoner.FilterBy("owner", owner),
+			bsoner.Lt("expires_at", now),
+		),
+		bsoner.DocSet("owner", owner).DocSet("expires_at", now.Add(ttl)),
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrLocked
+	}
+	return err
+}
+
+func (store *mongoStore) Unlock(ctx context.Context, owner string) error {
+	_, err := store.locks.DeleteOne(ctx, bsoner.FilterBy("_id", lockID).Set("owner", owner))
+	return err
+}
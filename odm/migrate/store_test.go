@@ -0,0 +1,78 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"testing"
+	"time"
+)
+
+// newTestDatabase connects to MONGODB_URI, the test is skipped when the variable is not set
+func newTestDatabase(t *testing.T) *mongo.Database {
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		t.Skip("MONGODB_URI is not set")
+	}
+
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	database := client.Database("framework_test_" + primitive.NewObjectID().Hex())
+	t.Cleanup(func() {
+		_ = database.Drop(ctx)
+		_ = client.Disconnect(ctx)
+	})
+	return database
+}
+
+func TestMongoStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(newTestDatabase(t), DefaultCollection)
+
+	if err := store.Lock(ctx, "first", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Lock(ctx, "first", time.Minute); err != nil {
+		t.Errorf("the owner should renew the lock, got %v", err)
+	}
+	if err := store.Lock(ctx, "second", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Errorf("expected ErrLocked, got %v", err)
+	}
+	if err := store.Unlock(ctx, "second"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Lock(ctx, "second", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Errorf("only the owner should release the lock, got %v", err)
+	}
+	if err := store.Unlock(ctx, "first"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Lock(ctx, "second", -time.Second); err != nil {
+		t.Fatalf("a released lock should be taken, got %v", err)
+	}
+	if err := store.Lock(ctx, "first", time.Minute); err != nil {
+		t.Errorf("an expired lock should be taken over, got %v", err)
+	}
+
+	for _, version := range []int64{2, 1} {
+		if err := store.Record(ctx, Record{Version: version, Name: "migration", AppliedAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Remove(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	records, err := store.Applied(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Version != 1 {
+		t.Errorf("unexpected records %+v", records)
+	}
+}
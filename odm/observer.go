@@ -0,0 +1,48 @@
+package odm
+
+import (
+	"errors"
+	"github.com/CloudyKit/framework/container"
+	"go.mongodb.org/mongo-driver/mongo"
+	"reflect"
+)
+
+// Observer observes the manager operations, ObserveOperation is called before each operation with the
+// registry of the manager and the returned func is called with the operation error, operations returning
+// mongo.ErrNoDocuments are successful. The metrics and tracing packages provide observers, ex:
+//
+//	kernel.Bootstrap(&odm.Component{Database: "app", Observers: odm.Observers{metrics.ODMObserver{}, tracing.ODMObserver{}}})
+type Observer interface {
+	ObserveOperation(registry *container.Registry, collection, operation string) (done func(err error))
+}
+
+// Observers the observers notified by the managers, provided by the registry, see Component.Observers
+type Observers []Observer
+
+var ObserversType = reflect.TypeOf(Observers(nil))
+
+// GetObservers returns the observers provided by the registry
+func GetObservers(registry *container.Registry) Observers {
+	observers, _ := registry.LoadType(ObserversType).(Observers)
+	return observers
+}
+
+// observe notifies the observers of the manager registry of the operation, the returned func ends the operation
+func (m *Manager) observe(operation string) func(err error) {
+	if m.Registry == nil {
+		return func(error) {}
+	}
+	observers := GetObservers(m.Registry)
+	done := make([]func(error), len(observers))
+	for i, observer := range observers {
+		done[i] = observer.ObserveOperation(m.Registry, m.Collection.Name(), operation)
+	}
+	return func(err error) {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = nil
+		}
+		for i := len(done) - 1; i >= 0; i-- {
+			done[i](err)
+		}
+	}
+}
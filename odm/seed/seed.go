@@ -0,0 +1,369 @@
+package seed
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/CloudyKit/framework/container"
+	"github.com/CloudyKit/framework/odm"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	// AliasField field naming a fixture document, the field is not inserted
+	AliasField = "_alias"
+	// RefField field of a sub document replaced by the _id of the document with the alias, ex: {"author_id": {"_ref": "alice"}}
+	RefField = "_ref"
+)
+
+// Fixture documents to be inserted in a collection
+type Fixture struct {
+	Collection string
+	Documents  []bson.D
+}
+
+// Seeder inserts fixtures through odm.Manager, so the odm events, traits and multitenant handlers run as in
+// the app. Fixtures are inserted in the order they were added, documents can reference documents inserted
+// before them by alias, see AliasField and RefField. Fixtures are written in extended JSON, ex: {"$date": "2024-01-31T10:00:00Z"}
+type Seeder struct {
+	Database *mongo.Database     // Database defaults to the database provided by Registry, see odm.Component
+	Registry *container.Registry // Registry given to the managers
+
+	fixtures []*Fixture
+	models   map[string]reflect.Type
+	ids      map[string]interface{}
+}
+
+// New creates a seeder of database
+func New(database *mongo.Database, registry *container.Registry) *Seeder {
+	return &Seeder{Database: database, Registry: registry}
+}
+
+// Model sets the type the documents of collection are decoded into before being inserted, so the
+// traits of the model run, ex: seeder.Model("users", User{})
+func (seeder *Seeder) Model(collection string, model interface{}) *Seeder {
+	typ := reflect.TypeOf(model)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if seeder.models == nil {
+		seeder.models = map[string]reflect.Type{}
+	}
+	seeder.models[collection] = typ
+	return seeder
+}
+
+// Add adds documents to be inserted in collection
+func (seeder *Seeder) Add(collection string, documents ...bson.D) *Seeder {
+	seeder.fixtures = append(seeder.fixtures, &Fixture{Collection: collection, Documents: documents})
+	return seeder
+}
+
+// Fixtures returns the added fixtures
+func (seeder *Seeder) Fixtures() []*Fixture {
+	return seeder.fixtures
+}
+
+// LoadFiles parses fixture files, files with the extension .jsonl hold one document per line, files with
+// the extension .json hold an array of documents, the collection in both cases is the file name without
+// the extension, or an object mapping collection names to arrays of documents
+func (seeder *Seeder) LoadFiles(paths ...string) error {
+	for _, path := range paths {
+		if err := seeder.loadFile(path); err != nil {
+			return fmt.Errorf("seed: %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// LoadDir parses the .json and .jsonl files in dir sorted by name, prefix the file names to order
+// the fixtures, ex: 01_users.json, 02_posts.jsonl
+func (seeder *Seeder) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if ext := filepath.Ext(entry.Name()); !entry.IsDir() && (ext == ".json" || ext == ".jsonl") {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return seeder.LoadFiles(paths...)
+}
+
+func (seeder *Seeder) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	name := filepath.Base(path)
+	ext := filepath.Ext(name)
+	collection := strings.TrimSuffix(name, ext)
+	if i := strings.IndexByte(collection, '_'); i >= 0 && strings.Trim(collection[:i], "0123456789") == "" {
+		// strips the ordering prefix, ex: 01_users
+		collection = collection[i+1:]
+	}
+
+	if ext == ".jsonl" {
+		return seeder.ParseLines(collection, file)
+	}
+	return seeder.Parse(collection, file)
+}
+
+// Parse parses an array of documents of collection or an object mapping collection names to arrays of documents
+func (seeder *Seeder) Parse(collection string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	data = bytes.TrimSpace(data)
+	wrapped := len(data) > 0 && data[0] == '['
+	if wrapped {
+		// wraps the array, extended json is decoded into documents only
+		data = append(append([]byte(`{"documents":`), data...), '}')
+	}
+
+	var root bson.D
+	if err := bson.UnmarshalExtJSON(data, false, &root); err != nil {
+		return err
+	}
+	for _, element := range root {
+		if !wrapped {
+			collection = element.Key
+		}
+		list, ok := element.Value.(primitive.A)
+		if !ok {
+			return fmt.Errorf("expected an array of documents in %s", element.Key)
+		}
+		documents := make([]bson.D, 0, len(list))
+		for i, item := range list {
+			document, ok := item.(primitive.D)
+			if !ok {
+				return fmt.Errorf("%s: item %d is not a document", collection, i)
+			}
+			documents = append(documents, bson.D(document))
+		}
+		seeder.Add(collection, documents...)
+	}
+	return nil
+}
+
+// ParseLines parses one document of collection per line, empty lines are skipped
+func (seeder *Seeder) ParseLines(collection string, r io.Reader) error {
+	var documents []bson.D
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var document bson.D
+		if err := bson.UnmarshalExtJSON(text, false, &document); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		documents = append(documents, document)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	seeder.Add(collection, documents...)
+	return nil
+}
+
+// ID returns the _id of the document inserted with alias
+func (seeder *Seeder) ID(alias string) (interface{}, bool) {
+	id, found := seeder.ids[alias]
+	return id, found
+}
+
+func (seeder *Seeder) manager(ctx context.Context, collection string) (*odm.Manager, error) {
+	database := seeder.Database
+	if database == nil && seeder.Registry != nil {
+		database, _ = seeder.Registry.LoadType(odm.DatabaseType).(*mongo.Database)
+	}
+	if database == nil {
+		return nil, fmt.Errorf("seed: no database, set Seeder.Database or bootstrap odm.Component")
+	}
+	return &odm.Manager{Context: ctx, Collection: database.Collection(collection), Registry: seeder.Registry}, nil
+}
+
+// Seed inserts the fixtures, the aliases of a previous Seed are discarded
+func (seeder *Seeder) Seed(ctx context.Context) error {
+	return seeder.seed(func(collection string) (inserter, error) {
+		manager, err := seeder.manager(ctx, collection)
+		if err != nil {
+			return nil, err
+		}
+		return func(document interface{}) (interface{}, error) {
+			result, err := manager.InsertOne(document)
+			if err != nil {
+				return nil, err
+			}
+			return result.InsertedID, nil
+		}, nil
+	})
+}
+
+// inserter inserts a document returning its _id
+type inserter func(document interface{}) (interface{}, error)
+
+// seed resolves the documents of the fixtures and inserts them with the inserter of their collection,
+// the id returned by the inserter is recorded under the alias of the document
+func (seeder *Seeder) seed(collection func(collection string) (inserter, error)) error {
+	seeder.ids = map[string]interface{}{}
+	for _, fixture := range seeder.fixtures {
+		insert, err := collection(fixture.Collection)
+		if err != nil {
+			return err
+		}
+		for i, document := range fixture.Documents {
+			alias, resolved, err := seeder.resolve(document)
+			if err != nil {
+				return fmt.Errorf("seed: %s document %d: %w", fixture.Collection, i, err)
+			}
+
+			model, err := seeder.model(fixture.Collection, resolved)
+			if err != nil {
+				return fmt.Errorf("seed: %s document %d: %w", fixture.Collection, i, err)
+			}
+			id, err := insert(model)
+			if err != nil {
+				return fmt.Errorf("seed: %s document %d: %w", fixture.Collection, i, err)
+			}
+			if alias != "" {
+				seeder.ids[alias] = id
+			}
+		}
+	}
+	return nil
+}
+
+// Truncate deletes the documents of the fixture collections and the collections passed in,
+// the deletes run through odm.Manager so the filters of the multitenant handlers apply
+func (seeder *Seeder) Truncate(ctx context.Context, collections ...string) error {
+	seen := map[string]bool{}
+	for _, fixture := range seeder.fixtures {
+		collections = append(collections, fixture.Collection)
+	}
+	for _, collection := range collections {
+		if seen[collection] {
+			continue
+		}
+		seen[collection] = true
+
+		manager, err := seeder.manager(ctx, collection)
+		if err != nil {
+			return err
+		}
+		if _, err := manager.DeleteMany(bson.D{}); err != nil {
+			return fmt.Errorf("seed: truncating %s: %w", collection, err)
+		}
+	}
+	return nil
+}
+
+// Reload truncates the fixture collections and seeds them again, ex: between test cases
+func (seeder *Seeder) Reload(ctx context.Context) error {
+	if err := seeder.Truncate(ctx); err != nil {
+		return err
+	}
+	return seeder.Seed(ctx)
+}
+
+// resolve returns a copy of document without the alias field, references are replaced by the ids of the
+// documents inserted, case the document has an alias and no _id a new object id is set
+func (seeder *Seeder) resolve(document bson.D) (alias string, resolved bson.D, err error) {
+	resolved = make(bson.D, 0, len(document)+1)
+	hasID := false
+	for _, element := range document {
+		switch element.Key {
+		case AliasField:
+			var ok bool
+			if alias, ok = element.Value.(string); !ok || alias == "" {
+				return "", nil, fmt.Errorf("invalid alias %v", element.Value)
+			}
+			if _, taken := seeder.ids[alias]; taken {
+				return "", nil, fmt.Errorf("alias %s already used", alias)
+			}
+			continue
+		case "_id":
+			hasID = true
+		}
+		value, err := seeder.resolveValue(element.Value)
+		if err != nil {
+			return "", nil, fmt.Errorf("%s: %w", element.Key, err)
+		}
+		resolved = append(resolved, bson.E{Key: element.Key, Value: value})
+	}
+	if alias != "" && !hasID {
+		resolved = append(bson.D{{Key: "_id", Value: primitive.NewObjectID()}}, resolved...)
+	}
+	return alias, resolved, nil
+}
+
+func (seeder *Seeder) resolveValue(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case primitive.D:
+		if len(value) == 1 && value[0].Key == RefField {
+			alias, _ := value[0].Value.(string)
+			id, found := seeder.ids[alias]
+			if !found {
+				return nil, fmt.Errorf("unknown alias %v, aliases must be inserted before their references", value[0].Value)
+			}
+			return id, nil
+		}
+		resolved := make(primitive.D, len(value))
+		for i, element := range value {
+			elementValue, err := seeder.resolveValue(element.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", element.Key, err)
+			}
+			resolved[i] = primitive.E{Key: element.Key, Value: elementValue}
+		}
+		return resolved, nil
+	case primitive.A:
+		resolved := make(primitive.A, len(value))
+		for i, item := range value {
+			itemValue, err := seeder.resolveValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+			resolved[i] = itemValue
+		}
+		return resolved, nil
+	}
+	return value, nil
+}
+
+// model decodes document into the model of collection, the document is returned when the collection has no model
+func (seeder *Seeder) model(collection string, document bson.D) (interface{}, error) {
+	typ, found := seeder.models[collection]
+	if !found {
+		return document, nil
+	}
+	data, err := bson.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	model := reflect.New(typ)
+	if err := bson.Unmarshal(data, model.Interface()); err != nil {
+		return nil, err
+	}
+	return model.Interface(), nil
+}
@@ -0,0 +1,109 @@
+package seed
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSeeder_LoadDir(t *testing.T) {
+	seeder := New(nil, nil)
+	if err := seeder.LoadDir("testdata"); err != nil {
+		t.Fatal(err)
+	}
+
+	fixtures := seeder.Fixtures()
+	if len(fixtures) != 2 || fixtures[0].Collection != "users" || fixtures[1].Collection != "posts" {
+		t.Fatalf("unexpected fixtures %+v", fixtures)
+	}
+	if len(fixtures[0].Documents) != 2 || len(fixtures[1].Documents) != 2 {
+		t.Errorf("unexpected documents %+v", fixtures)
+	}
+
+	// seeds through a stub inserter returning the _id of the resolved documents
+	var resolved []bson.D
+	err := seeder.seed(func(collection string) (inserter, error) {
+		return func(document interface{}) (interface{}, error) {
+			d := document.(bson.D)
+			resolved = append(resolved, d)
+			return d.Map()["_id"], nil
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resolved) != 4 {
+		t.Fatalf("expected 4 inserted documents, got %d", len(resolved))
+	}
+	if id, _ := seeder.ID("alice"); id != resolved[0].Map()["_id"] {
+		t.Errorf("alias alice not recorded, got %v", id)
+	}
+
+	alice := resolved[0].Map()
+	if _, ok := alice["_id"].(primitive.ObjectID); !ok || alice[AliasField] != nil {
+		t.Errorf("unexpected aliased document %v", alice)
+	}
+	if joinedAt, _ := alice["joined_at"].(primitive.DateTime); !joinedAt.Time().Equal(time.Date(2024, time.January, 31, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected date %v", alice["joined_at"])
+	}
+
+	reply := resolved[3].Map()
+	if reply["author_id"] != resolved[1].Map()["_id"] {
+		t.Errorf("reference to bob not resolved: %v", reply)
+	}
+	thread := reply["thread"].(primitive.A)[0].(primitive.D).Map()
+	if thread["post_id"] != resolved[2].Map()["_id"] {
+		t.Errorf("nested reference to hello not resolved: %v", thread)
+	}
+}
+
+func TestSeeder_Errors(t *testing.T) {
+	seeder := New(nil, nil)
+	seeder.ids = map[string]interface{}{"alice": 1}
+
+	for document, expected := range map[string]string{
+		`{"author_id": {"_ref": "carol"}}`: "unknown alias carol",
+		`{"_alias": "alice"}`:              "alias alice already used",
+		`{"_alias": 10}`:                   "invalid alias",
+	} {
+		var d bson.D
+		if err := bson.UnmarshalExtJSON([]byte(document), false, &d); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := seeder.resolve(d); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: expected error %q, got %v", document, expected, err)
+		}
+	}
+
+	if err := seeder.Parse("users", strings.NewReader(`{"users": {"name": "x"}}`)); err == nil {
+		t.Error("expected an error for a collection without an array")
+	}
+
+	seeder.Add("users", bson.D{{Key: "name", Value: "x"}})
+	if err := seeder.Seed(context.Background()); err == nil || !strings.Contains(err.Error(), "no database") {
+		t.Errorf("expected no database error, got %v", err)
+	}
+}
+
+type user struct {
+	ID   primitive.ObjectID `bson:"_id"`
+	Name string             `bson:"name"`
+}
+
+func TestSeeder_Model(t *testing.T) {
+	seeder := New(nil, nil).Model("users", &user{})
+	id := primitive.NewObjectID()
+	model, err := seeder.model("users", bson.D{{Key: "_id", Value: id}, {Key: "name", Value: "Alice"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u, ok := model.(*user); !ok || u.ID != id || u.Name != "Alice" {
+		t.Errorf("unexpected model %#v", model)
+	}
+	if document, _ := seeder.model("posts", bson.D{}); document == nil {
+		t.Error("documents without model should be inserted as is")
+	}
+}
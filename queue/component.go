@@ -0,0 +1,64 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package queue
+
+import (
+	"context"
+	"github.com/CloudyKit/framework/app"
+)
+
+// Component provides the queue and a client in the kernel registry, and runs the worker with the
+// kernel, see app.Kernel.Start. Handlers are registered in the worker, ex: queue.Handle(component.Worker, "mail", sendMail)
+type Component struct {
+	Queue  Queue   // Queue defaults to an in-memory queue
+	Client *Client // Client defaults to a client pushing into Queue
+	Worker *Worker // Worker defaults to a worker processing Queue, a worker without handlers is not started
+}
+
+func (component *Component) Bootstrap(a *app.Kernel) {
+	if component.Queue == nil {
+		component.Queue = NewMemory()
+	}
+	if component.Client == nil {
+		component.Client = NewClient(component.Queue)
+	}
+	if component.Worker == nil {
+		component.Worker = NewWorker(component.Queue, a.Registry)
+	} else if component.Worker.Registry == nil {
+		component.Worker.Registry = a.Registry
+	}
+
+	a.Registry.WithTypeAndValue(QueueType, component.Queue)
+	a.Registry.WithTypeAndValue(ClientType, component.Client)
+}
+
+func (component *Component) Start(ctx context.Context) error {
+	if !component.Worker.hasHandlers() {
+		return nil
+	}
+	return component.Worker.Start(ctx)
+}
+
+func (component *Component) Stop(ctx context.Context) error {
+	return component.Worker.Stop(ctx)
+}
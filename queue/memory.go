@@ -0,0 +1,145 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package queue
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory in-memory queue driver, the messages are lost on restart, ex: tests and development
+type Memory struct {
+	mx       sync.Mutex
+	messages map[string]*Message
+}
+
+// NewMemory creates an in-memory queue
+func NewMemory() *Memory {
+	return &Memory{messages: map[string]*Message{}}
+}
+
+func (memory *Memory) Push(_ context.Context, message *Message) error {
+	memory.mx.Lock()
+	defer memory.mx.Unlock()
+	if memory.messages == nil {
+		memory.messages = map[string]*Message{}
+	}
+	stored := *message
+	memory.messages[message.ID] = &stored
+	return nil
+}
+
+func (memory *Memory) Reserve(_ context.Context, queue string, lease time.Duration) (*Message, error) {
+	memory.mx.Lock()
+	defer memory.mx.Unlock()
+
+	now := time.Now()
+	var next *Message
+	for _, message := range memory.messages {
+		if message.Queue != queue || message.Status != StatusReady || message.AvailableAt.After(now) {
+			continue
+		}
+		if next == nil || message.AvailableAt.Before(next.AvailableAt) {
+			next = message
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	next.Attempts++
+	next.AvailableAt = now.Add(lease)
+	next.Token = newID()
+	reserved := *next
+	return &reserved, nil
+}
+
+// reserved returns the stored message reserved with the token of message, called with the memory locked
+func (memory *Memory) reserved(message *Message) (*Message, error) {
+	stored, found := memory.messages[message.ID]
+	if !found || stored.Status != StatusReady || message.Token == "" || stored.Token != message.Token {
+		return nil, ErrLeaseLost
+	}
+	return stored, nil
+}
+
+func (memory *Memory) Ack(_ context.Context, message *Message) error {
+	memory.mx.Lock()
+	defer memory.mx.Unlock()
+	if _, err := memory.reserved(message); err != nil {
+		return err
+	}
+	delete(memory.messages, message.ID)
+	return nil
+}
+
+func (memory *Memory) Retry(_ context.Context, message *Message, at time.Time, cause error) error {
+	memory.mx.Lock()
+	defer memory.mx.Unlock()
+	stored, err := memory.reserved(message)
+	if err != nil {
+		return err
+	}
+	stored.AvailableAt = at
+	stored.LastError = errorString(cause)
+	stored.Token = ""
+	return nil
+}
+
+func (memory *Memory) Bury(_ context.Context, message *Message, cause error) error {
+	memory.mx.Lock()
+	defer memory.mx.Unlock()
+	stored, err := memory.reserved(message)
+	if err != nil {
+		return err
+	}
+	stored.Status = StatusDead
+	stored.FailedAt = time.Now()
+	stored.LastError = errorString(cause)
+	stored.Token = ""
+	return nil
+}
+
+func (memory *Memory) Dead(_ context.Context, queue string) ([]*Message, error) {
+	memory.mx.Lock()
+	defer memory.mx.Unlock()
+
+	var dead []*Message
+	for _, message := range memory.messages {
+		if message.Queue == queue && message.Status == StatusDead {
+			stored := *message
+			dead = append(dead, &stored)
+		}
+	}
+	sort.Slice(dead, func(i, j int) bool { return dead[i].FailedAt.Before(dead[j].FailedAt) })
+	return dead, nil
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,155 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mongostore
+
+import (
+	"context"
+	"errors"
+	"github.com/CloudyKit/framework/odm"
+	"github.com/CloudyKit/framework/odm/bsoner"
+	"github.com/CloudyKit/framework/queue"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// Store persistent queue driver storing the messages in the collection of an odm.Manager, a
+// reserved message is leased by moving its available_at forward so the messages of a crashed
+// worker are processed again after the lease, the messages are settled only with the token of their
+// current reservation, dead letters stay in the collection with status dead
+type Store struct {
+	Manager *odm.Manager
+}
+
+// New creates a store using the collection of manager, see Store.EnsureIndexes
+func New(manager *odm.Manager) *Store {
+	return &Store{Manager: manager}
+}
+
+// manager returns a copy of the store manager running with ctx
+func (store *Store) manager(ctx context.Context) *odm.Manager {
+	manager := *store.Manager
+	manager.Context = ctx
+	return &manager
+}
+
+// EnsureIndexes creates the index used to reserve the messages
+func (store *Store) EnsureIndexes(ctx context.Context) error {
+	return store.manager(ctx).EnsureIndex("queue_status_available_at", primitive.D{
+		{Key: "queue", Value: 1},
+		{Key: "status", Value: 1},
+		{Key: "available_at", Value: 1},
+	}, false)
+}
+
+func (store *Store) Push(ctx context.Context, message *queue.Message) error {
+	_, err := store.manager(ctx).InsertOne(message)
+	return err
+}
+
+func (store *Store) Reserve(ctx context.Context, queueName string, lease time.Duration) (*queue.Message, error) {
+	now := time.Now()
+	result := store.manager(ctx).FindOneAndUpdate(
+		bsoner.FilterBy("queue", queueName).Set("status", queue.StatusReady).Lte("available_at", now),
+		bsoner.DocSet("available_at", now.Add(lease)).
+			DocSet("token", primitive.NewObjectID().Hex()).
+			Set("$inc", bsoner.NewDocumentSet("attempts", 1)),
+		options.FindOneAndUpdate().SetSort(primitive.D{{Key: "available_at", Value: 1}}).SetReturnDocument(options.After),
+	)
+	if result == nil {
+		return nil, errors.New("queue/mongostore: reserve canceled by an event handler")
+	}
+
+	message := new(queue.Message)
+	if err := result.Decode(message); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return message, nil
+}
+
+// reserved filters message by its id and the token of its current reservation
+func reserved(message *queue.Message) *bsoner.Filter {
+	return bsoner.FilterBy("_id", message.ID).Set("status", queue.StatusReady).Set("token", message.Token)
+}
+
+func (store *Store) Ack(ctx context.Context, message *queue.Message) error {
+	result, err := store.manager(ctx).DeleteOne(reserved(message))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return queue.ErrLeaseLost
+	}
+	return nil
+}
+
+func (store *Store) Retry(ctx context.Context, message *queue.Message, at time.Time, cause error) error {
+	return store.settle(ctx, message, bsoner.DocSet("available_at", at).
+		DocSet("last_error", errorString(cause)).
+		Set("$unset", bsoner.NewDocumentSet("token", "")))
+}
+
+func (store *Store) Bury(ctx context.Context, message *queue.Message, cause error) error {
+	return store.settle(ctx, message, bsoner.DocSet("status", queue.StatusDead).
+		DocSet("failed_at", time.Now()).
+		DocSet("last_error", errorString(cause)).
+		Set("$unset", bsoner.NewDocumentSet("token", "")))
+}
+
+// settle updates the message reserved with the token of message
+func (store *Store) settle(ctx context.Context, message *queue.Message, update interface{}) error {
+	result, err := store.manager(ctx).UpdateOne(reserved(message), update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return queue.ErrLeaseLost
+	}
+	return nil
+}
+
+func (store *Store) Dead(ctx context.Context, queueName string) ([]*queue.Message, error) {
+	manager := store.manager(ctx)
+	cursor, err := manager.Find(
+		bsoner.FilterBy("queue", queueName).Set("status", queue.StatusDead),
+		options.Find().SetSort(primitive.D{{Key: "failed_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var dead []*queue.Message
+	err = cursor.All(ctx, &dead)
+	return dead, err
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mongostore
+
+import (
+	"context"
+	"errors"
+	"github.com/CloudyKit/framework/odm"
+	"github.com/CloudyKit/framework/queue"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"testing"
+	"time"
+)
+
+// newTestStore connects to MONGODB_URI, the test is skipped when the variable is not set
+func newTestStore(t *testing.T) *Store {
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		t.Skip("MONGODB_URI is not set")
+	}
+
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	database := client.Database("framework_test_" + primitive.NewObjectID().Hex())
+	t.Cleanup(func() {
+		_ = database.Drop(ctx)
+		_ = client.Disconnect(ctx)
+	})
+
+	store := New(&odm.Manager{Context: ctx, Collection: database.Collection("jobs")})
+	if err := store.EnsureIndexes(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestStore_Lease(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+	if _, err := queue.NewClient(store).Push(ctx, "greet", map[string]string{"name": "lease"}, queue.MaxAttempts(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := store.Reserve(ctx, queue.DefaultQueue, -time.Second)
+	if err != nil || expired == nil {
+		t.Fatalf("expected a message got %v", err)
+	}
+	current, err := store.Reserve(ctx, queue.DefaultQueue, time.Minute)
+	if err != nil || current == nil || current.Token == expired.Token || current.Attempts != 2 {
+		t.Fatalf("an expired message should be reserved again with a new token, got %+v %v", current, err)
+	}
+	if message, _ := store.Reserve(ctx, queue.DefaultQueue, time.Minute); message != nil {
+		t.Errorf("a leased message should not be reserved, got %+v", message)
+	}
+
+	if err := store.Ack(ctx, expired); !errors.Is(err, queue.ErrLeaseLost) {
+		t.Errorf("Ack with an expired lease should fail, got %v", err)
+	}
+	if err := store.Retry(ctx, expired, time.Now(), nil); !errors.Is(err, queue.ErrLeaseLost) {
+		t.Errorf("Retry with an expired lease should fail, got %v", err)
+	}
+
+	if err := store.Bury(ctx, current, errors.New("failed")); err != nil {
+		t.Fatal(err)
+	}
+	dead, err := store.Dead(ctx, queue.DefaultQueue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dead) != 1 || dead[0].Token != "" || dead[0].LastError != "failed" {
+		t.Errorf("unexpected dead letters %+v", dead)
+	}
+	if err := store.Ack(ctx, dead[0]); !errors.Is(err, queue.ErrLeaseLost) {
+		t.Errorf("a buried message should not be settled again, got %v", err)
+	}
+}
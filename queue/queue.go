@@ -0,0 +1,173 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/CloudyKit/framework/container"
+	"reflect"
+	"time"
+)
+
+const (
+	DefaultQueue       = "default"
+	DefaultMaxAttempts = 5
+
+	StatusReady = "ready"
+	StatusDead  = "dead"
+)
+
+var (
+	QueueType  = reflect.TypeOf((*Queue)(nil)).Elem()
+	ClientType = reflect.TypeOf((*Client)(nil))
+
+	// ErrLeaseLost is returned when settling a message whose reservation is not held anymore, the lease
+	// expired and the message was reserved again, acknowledged, retried or buried by another worker
+	ErrLeaseLost = errors.New("queue: message lease lost")
+)
+
+// GetClient returns the queue client provided by the registry or nil
+func GetClient(registry *container.Registry) *Client {
+	client, _ := registry.LoadType(ClientType).(*Client)
+	return client
+}
+
+// Message a job stored in a queue
+type Message struct {
+	ID          string          `json:"id" bson:"_id"`
+	Queue       string          `json:"queue" bson:"queue"`
+	Type        string          `json:"type" bson:"type"` // Type job type, selects the worker handler
+	Payload     json.RawMessage `json:"payload" bson:"payload"`
+	Status      string          `json:"status" bson:"status"`
+	Attempts    int             `json:"attempts" bson:"attempts"`
+	MaxAttempts int             `json:"max_attempts" bson:"max_attempts"`
+	AvailableAt time.Time       `json:"available_at" bson:"available_at"` // AvailableAt the message can be reserved after, a reserved message is available again when the lease expires
+	CreatedAt   time.Time       `json:"created_at" bson:"created_at"`
+	FailedAt    time.Time       `json:"failed_at,omitempty" bson:"failed_at,omitempty"`
+	LastError   string          `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	Token       string          `json:"token,omitempty" bson:"token,omitempty"` // Token identifies the current reservation, set by Reserve
+}
+
+// Queue stores the messages, implementations must be safe for concurrent use, see Memory and mongostore.Store
+type Queue interface {
+	// Push stores a ready message
+	Push(ctx context.Context, message *Message) error
+	// Reserve leases the next available message of queue incrementing its attempts and setting a new
+	// Token, the message is available again after lease unless acknowledged, retried or buried, returns
+	// nil when the queue is empty
+	Reserve(ctx context.Context, queue string, lease time.Duration) (*Message, error)
+	// Ack removes a processed message, Ack, Retry and Bury return ErrLeaseLost when the message Token
+	// doesn't match the current reservation of the message
+	Ack(ctx context.Context, message *Message) error
+	// Retry releases the message to be reserved again at
+	Retry(ctx context.Context, message *Message, at time.Time, cause error) error
+	// Bury moves the message to the dead letters
+	Bury(ctx context.Context, message *Message, cause error) error
+	// Dead returns the dead letters of queue
+	Dead(ctx context.Context, queue string) ([]*Message, error)
+}
+
+// Option changes a message before it is pushed
+type Option func(message *Message)
+
+// Delay delays the message by delay
+func Delay(delay time.Duration) Option {
+	return func(message *Message) {
+		message.AvailableAt = message.AvailableAt.Add(delay)
+	}
+}
+
+// At delays the message until at
+func At(at time.Time) Option {
+	return func(message *Message) {
+		message.AvailableAt = at
+	}
+}
+
+// OnQueue pushes the message into the queue name
+func OnQueue(name string) Option {
+	return func(message *Message) {
+		message.Queue = name
+	}
+}
+
+// MaxAttempts sets the number of attempts before the message is buried
+func MaxAttempts(attempts int) Option {
+	return func(message *Message) {
+		message.MaxAttempts = attempts
+	}
+}
+
+// Client pushes jobs into a queue
+type Client struct {
+	Queue       Queue
+	Name        string // Name default queue name, defaults to DefaultQueue
+	MaxAttempts int    // MaxAttempts default attempts, defaults to DefaultMaxAttempts
+}
+
+// NewClient creates a client pushing into queue
+func NewClient(queue Queue) *Client {
+	return &Client{Queue: queue}
+}
+
+// Push pushes a job of type jobType, the payload is json encoded and decoded into the payload type
+// of the handler, see Handle
+func (client *Client) Push(ctx context.Context, jobType string, payload interface{}, options ...Option) (*Message, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	message := &Message{
+		ID:          newID(),
+		Queue:       client.Name,
+		Type:        jobType,
+		Payload:     data,
+		Status:      StatusReady,
+		MaxAttempts: client.MaxAttempts,
+		AvailableAt: now,
+		CreatedAt:   now,
+	}
+	if message.Queue == "" {
+		message.Queue = DefaultQueue
+	}
+	if message.MaxAttempts <= 0 {
+		message.MaxAttempts = DefaultMaxAttempts
+	}
+	for _, option := range options {
+		option(message)
+	}
+
+	return message, client.Queue.Push(ctx, message)
+}
+
+func newID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,260 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"github.com/CloudyKit/framework/container"
+	"testing"
+	"time"
+)
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+type greeter struct {
+	prefix string
+}
+
+func TestWorker(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemory()
+	client := NewClient(memory)
+
+	registry := container.New()
+	defer registry.Dispose()
+	registry.WithValues(&greeter{prefix: "hello"})
+
+	worker := NewWorker(memory, registry)
+	worker.Backoff = func(int) time.Duration { return 0 }
+
+	var greeted []string
+	Handle(worker, "greet", func(ctx context.Context, registry *container.Registry, payload greeting) error {
+		var g *greeter
+		registry.Load(&g)
+		greeted = append(greeted, g.prefix+" "+payload.Name)
+		return nil
+	})
+
+	failures := 0
+	worker.HandleMessage("fail", func(ctx context.Context, registry *container.Registry, message *Message) error {
+		failures++
+		return errors.New("boom")
+	})
+	worker.HandleMessage("panic", func(ctx context.Context, registry *container.Registry, message *Message) error {
+		panic("crashed")
+	})
+	worker.HandleMessage("permanent", func(ctx context.Context, registry *container.Registry, message *Message) error {
+		return Permanent(errors.New("invalid"))
+	})
+
+	if _, err := client.Push(ctx, "greet", greeting{Name: "world"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Push(ctx, "later", nil, Delay(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	for _, jobType := range []string{"fail", "panic", "permanent", "unknown"} {
+		if _, err := client.Push(ctx, jobType, nil, MaxAttempts(3)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; ; i++ {
+		processed, err := worker.Work(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !processed {
+			break
+		}
+		if i > 20 {
+			t.Fatal("queue is not draining")
+		}
+	}
+
+	if len(greeted) != 1 || greeted[0] != "hello world" {
+		t.Errorf("unexpected greetings %v", greeted)
+	}
+	if failures != 3 {
+		t.Errorf("expected 3 attempts of the failing job, got %d", failures)
+	}
+
+	dead, err := memory.Dead(ctx, DefaultQueue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attempts := map[string]int{}
+	for _, message := range dead {
+		attempts[message.Type] = message.Attempts
+		if message.Status != StatusDead || message.LastError == "" || message.FailedAt.IsZero() {
+			t.Errorf("unexpected dead message %+v", message)
+		}
+	}
+	expected := map[string]int{"fail": 3, "panic": 3, "permanent": 1, "unknown": 1}
+	if len(attempts) != len(expected) {
+		t.Fatalf("expected dead messages %v, got %v", expected, attempts)
+	}
+	for jobType, count := range expected {
+		if attempts[jobType] != count {
+			t.Errorf("expected %d attempts of %s, got %d", count, jobType, attempts[jobType])
+		}
+	}
+
+	// the delayed message is still waiting
+	if message, _ := memory.Reserve(ctx, DefaultQueue, time.Minute); message != nil {
+		t.Errorf("delayed message %s reserved before its time", message.Type)
+	}
+}
+
+func TestWorker_StartStop(t *testing.T) {
+	memory := NewMemory()
+	worker := NewWorker(memory, nil)
+	worker.PollInterval = time.Millisecond
+	worker.Concurrency = 2
+
+	done := make(chan string, 1)
+	Handle(worker, "greet", func(ctx context.Context, registry *container.Registry, payload greeting) error {
+		done <- payload.Name
+		return nil
+	})
+
+	if err := worker.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewClient(memory).Push(context.Background(), "greet", greeting{Name: "async"}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case name := <-done:
+		if name != "async" {
+			t.Errorf("unexpected payload %q", name)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("job was not processed")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := worker.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWorker_Restart(t *testing.T) {
+	memory := NewMemory()
+	worker := NewWorker(memory, nil)
+	worker.PollInterval = time.Millisecond
+
+	started, release, done := make(chan struct{}), make(chan struct{}), make(chan string, 1)
+	Handle(worker, "greet", func(ctx context.Context, registry *container.Registry, payload greeting) error {
+		if payload.Name == "blocked" {
+			close(started)
+			<-release
+		}
+		done <- payload.Name
+		return nil
+	})
+
+	client := NewClient(memory)
+	worker.Start(context.Background())
+	client.Push(context.Background(), "greet", greeting{Name: "blocked"})
+	<-started
+
+	// the stop times out while the job is running, a new start doesn't reuse the wait group of the pending stop
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := worker.Stop(ctx); err == nil {
+		t.Fatal("expected the stop to time out")
+	}
+	worker.Start(context.Background())
+	close(release)
+	<-done
+
+	client.Push(context.Background(), "greet", greeting{Name: "restarted"})
+	select {
+	case name := <-done:
+		if name != "restarted" {
+			t.Errorf("unexpected payload %q", name)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("job was not processed after the restart")
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := worker.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemory_Lease(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemory()
+	if _, err := NewClient(memory).Push(ctx, "greet", greeting{Name: "lease"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expired, _ := memory.Reserve(ctx, DefaultQueue, -time.Second)
+	current, _ := memory.Reserve(ctx, DefaultQueue, time.Minute)
+	if expired == nil || current == nil || expired.Token == current.Token {
+		t.Fatalf("an expired message should be reserved again with a new token, got %+v %+v", expired, current)
+	}
+
+	if err := memory.Ack(ctx, expired); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("Ack with an expired lease should fail, got %v", err)
+	}
+	if err := memory.Retry(ctx, expired, time.Now(), nil); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("Retry with an expired lease should fail, got %v", err)
+	}
+	if err := memory.Bury(ctx, expired, nil); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("Bury with an expired lease should fail, got %v", err)
+	}
+
+	if err := memory.Retry(ctx, current, time.Now(), errors.New("retry")); err != nil {
+		t.Fatal(err)
+	}
+	if err := memory.Ack(ctx, current); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("a retried message should not be settled again, got %v", err)
+	}
+
+	current, _ = memory.Reserve(ctx, DefaultQueue, time.Minute)
+	if err := memory.Ack(ctx, current); err != nil {
+		t.Fatal(err)
+	}
+	if err := memory.Ack(ctx, current); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("an acknowledged message should not be settled again, got %v", err)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Second, 10*time.Second)
+	for attempt, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		if delay := backoff(attempt + 1); delay != expected {
+			t.Errorf("attempt %d: expected %s, got %s", attempt+1, expected, delay)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/CloudyKit/framework/container"
+	"github.com/CloudyKit/framework/request"
+	"sync"
+	"time"
+)
+
+// HandlerFunc processes the raw payload of a message, registry is forked from the worker registry
+type HandlerFunc func(ctx context.Context, registry *container.Registry, message *Message) error
+
+// Handle registers a typed handler for jobType, the message payload is decoded into T
+func Handle[T any](worker *Worker, jobType string, handler func(ctx context.Context, registry *container.Registry, payload T) error) {
+	worker.HandleMessage(jobType, func(ctx context.Context, registry *container.Registry, message *Message) error {
+		var payload T
+		if err := json.Unmarshal(message.Payload, &payload); err != nil {
+			return Permanent(fmt.Errorf("queue: decoding payload of %s: %w", jobType, err))
+		}
+		return handler(ctx, registry, payload)
+	})
+}
+
+type permanentError struct {
+	err error
+}
+
+func (err *permanentError) Error() string {
+	return err.err.Error()
+}
+
+func (err *permanentError) Unwrap() error {
+	return err.err
+}
+
+// Permanent wraps err so that the message is buried without retries
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// ExponentialBackoff returns the delay before the retry of attempt, base doubled each attempt up to max
+func ExponentialBackoff(base, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		delay := base
+		for i := 1; i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			delay = max
+		}
+		return delay
+	}
+}
+
+// Worker processes the messages of the queues with a pool of goroutines, failed messages are retried
+// with backoff and buried after the max attempts
+type Worker struct {
+	Queue        Queue
+	Registry     *container.Registry             // Registry forked for each message
+	Queues       []string                        // Queues processed, defaults to DefaultQueue
+	Concurrency  int                             // Concurrency number of goroutines, defaults to 1
+	PollInterval time.Duration                   // PollInterval wait when the queues are empty, defaults to 1s
+	Lease        time.Duration                   // Lease time a message is reserved, defaults to 5m
+	Backoff      func(attempt int) time.Duration // Backoff delay before a retry, defaults to ExponentialBackoff(time.Second, time.Hour)
+
+	mx       sync.Mutex
+	handlers map[string]HandlerFunc
+	cycle    *cycle
+	once     sync.Once
+}
+
+// cycle the state of a Start/Stop cycle, each Start creates a new cycle so that a Stop
+// waiting for the goroutines of a previous cycle doesn't share the wait group of the next one
+type cycle struct {
+	cancel context.CancelFunc
+	loops  sync.WaitGroup
+}
+
+// NewWorker creates a worker processing queue
+func NewWorker(queue Queue, registry *container.Registry) *Worker {
+	return &Worker{Queue: queue, Registry: registry}
+}
+
+// HandleMessage registers the handler of the messages of jobType, see Handle
+func (worker *Worker) HandleMessage(jobType string, handler HandlerFunc) {
+	worker.mx.Lock()
+	defer worker.mx.Unlock()
+	if worker.handlers == nil {
+		worker.handlers = map[string]HandlerFunc{}
+	}
+	worker.handlers[jobType] = handler
+}
+
+func (worker *Worker) handler(jobType string) HandlerFunc {
+	worker.mx.Lock()
+	defer worker.mx.Unlock()
+	return worker.handlers[jobType]
+}
+
+func (worker *Worker) hasHandlers() bool {
+	worker.mx.Lock()
+	defer worker.mx.Unlock()
+	return len(worker.handlers) > 0
+}
+
+// Start starts the worker goroutines, see app.Starter
+func (worker *Worker) Start(_ context.Context) error {
+	worker.mx.Lock()
+	defer worker.mx.Unlock()
+
+	if worker.cycle != nil {
+		return nil
+	}
+	worker.once.Do(worker.defaults)
+
+	current := new(cycle)
+	var ctx context.Context
+	ctx, current.cancel = context.WithCancel(context.Background())
+	worker.cycle = current
+	for i := 0; i < worker.Concurrency; i++ {
+		current.loops.Add(1)
+		go worker.loop(ctx, current)
+	}
+	return nil
+}
+
+// Stop stops the worker goroutines and waits the messages being processed until ctx is done,
+// the messages not finished are available again when their lease expires, see app.Stopper
+func (worker *Worker) Stop(ctx context.Context) error {
+	worker.mx.Lock()
+	current := worker.cycle
+	worker.cycle = nil
+	worker.mx.Unlock()
+
+	if current == nil {
+		return nil
+	}
+	current.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		current.loops.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("queue: waiting workers: %w", ctx.Err())
+	}
+}
+
+func (worker *Worker) defaults() {
+	if len(worker.Queues) == 0 {
+		worker.Queues = []string{DefaultQueue}
+	}
+	if worker.Concurrency <= 0 {
+		worker.Concurrency = 1
+	}
+	if worker.PollInterval <= 0 {
+		worker.PollInterval = time.Second
+	}
+	if worker.Lease <= 0 {
+		worker.Lease = 5 * time.Minute
+	}
+	if worker.Backoff == nil {
+		worker.Backoff = ExponentialBackoff(time.Second, time.Hour)
+	}
+}
+
+func (worker *Worker) loop(ctx context.Context, current *cycle) {
+	defer current.loops.Done()
+	for {
+		processed, err := worker.Work(ctx)
+		if err != nil && ctx.Err() == nil {
+			request.GetLogger(worker.Registry).Error("queue: reserve", "error", err)
+		}
+		if processed {
+			continue
+		}
+
+		timer := time.NewTimer(worker.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Work reserves and processes one message of the worker queues, processed is false when the queues are empty
+func (worker *Worker) Work(ctx context.Context) (processed bool, err error) {
+	worker.once.Do(worker.defaults)
+	for _, queue := range worker.Queues {
+		message, err := worker.Queue.Reserve(ctx, queue, worker.Lease)
+		if err != nil {
+			return false, err
+		}
+		if message != nil {
+			worker.process(ctx, message)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// process runs the handler of message, acknowledging, retrying or burying the message
+func (worker *Worker) process(ctx context.Context, message *Message) {
+	err := worker.execute(ctx, message)
+
+	// the message is settled even when the worker is stopping
+	settle := context.WithoutCancel(ctx)
+	var permanent *permanentError
+	switch {
+	case err == nil:
+		err = worker.Queue.Ack(settle, message)
+	case errors.As(err, &permanent) || message.Attempts >= message.MaxAttempts:
+		request.GetLogger(worker.Registry).Error("queue: job buried", "job", message.Type, "id", message.ID, "attempts", message.Attempts, "error", err)
+		err = worker.Queue.Bury(settle, message, err)
+	default:
+		err = worker.Queue.Retry(settle, message, time.Now().Add(worker.Backoff(message.Attempts)), err)
+	}
+
+	if err != nil {
+		request.GetLogger(worker.Registry).Error("queue: settling message", "job", message.Type, "id", message.ID, "error", err)
+	}
+}
+
+// execute runs the handler with a forked registry, panics are returned as errors
+func (worker *Worker) execute(ctx context.Context, message *Message) (err error) {
+	handler := worker.handler(message.Type)
+	if handler == nil {
+		return Permanent(fmt.Errorf("queue: no handler for job %s", message.Type))
+	}
+
+	registry := worker.Registry
+	if registry == nil {
+		registry = container.New()
+	} else {
+		registry = registry.Fork()
+	}
+	defer registry.Dispose()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("queue: job %s panicked: %v", message.Type, recovered)
+		}
+	}()
+	return handler(ctx, registry, message)
+}
@@ -0,0 +1,267 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/CloudyKit/framework/container"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+const (
+	// DefaultMaxBodySize max size of the body decoded by Context.Bind
+	DefaultMaxBodySize = 10 << 20
+)
+
+var BinderType = reflect.TypeOf((*Binder)(nil))
+
+// DefaultBinder used by Context.Bind when the registry doesn't provide a Binder
+var DefaultBinder = &Binder{}
+
+// GetBinder gets the Binder from the Registry context, DefaultBinder is returned when the registry has no binder
+func GetBinder(cdi *container.Registry) *Binder {
+	if cdi != nil {
+		if binder, _ := cdi.LoadType(BinderType).(*Binder); binder != nil {
+			return binder
+		}
+	}
+	return DefaultBinder
+}
+
+// Binder settings of Context.Bind, provide a binder in the registry to change the settings,
+// ex: registry.WithValues(&request.Binder{MaxBodySize: 1 << 20})
+type Binder struct {
+	MaxBodySize          int64  // MaxBodySize defaults to DefaultMaxBodySize, a negative value disables the limit
+	ContentTypeParameter string // ContentTypeParameter query parameter overriding the content type, defaults to "contentType", "-" disables the override
+}
+
+func (binder *Binder) maxBodySize() int64 {
+	if binder.MaxBodySize == 0 {
+		return DefaultMaxBodySize
+	}
+	return binder.MaxBodySize
+}
+
+func (binder *Binder) contentTypeParameter() string {
+	if binder.ContentTypeParameter == "" {
+		return "contentType"
+	}
+	return binder.ContentTypeParameter
+}
+
+// Bindable marks a struct as bound from the request when it is received as a controller action argument
+// and the registry doesn't provide it, embed it in the struct,
+// ex: type UserForm struct { request.Bindable; Name string }
+type Bindable struct{}
+
+func (Bindable) bindable() {}
+
+var bindableType = reflect.TypeOf((*interface{ bindable() })(nil)).Elem()
+
+// IsBindable reports if typ, a struct or a pointer to a struct, embeds Bindable
+func IsBindable(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Ptr {
+		typ = reflect.PtrTo(typ)
+	}
+	return typ.Elem().Kind() == reflect.Struct && typ.Implements(bindableType)
+}
+
+// BindError an error binding a source of the request into the target, Context.Bind returns
+// BindError wrapped in an *Error carrying the status code, see StatusOf
+type BindError struct {
+	Source string // Source "query", "body" or "route"
+	Field  string // Field path of the field in the source when known, ex: "address.city", "items[0]"
+	Err    error
+}
+
+func (e *BindError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("bind %s field %q: %s", e.Source, e.Field, e.Err)
+	}
+	return fmt.Sprintf("bind %s: %s", e.Source, e.Err)
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+func bindError(status int, source string, err error) error {
+	bindErr := &BindError{Source: source, Err: err}
+
+	var pathErr *formPathError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &pathErr):
+		bindErr.Field = pathErr.Path
+	case errors.As(err, &typeErr):
+		bindErr.Field = typeErr.Field
+	}
+	return WrapError(status, bindErr)
+}
+
+// Bind decodes the request into target, the query values are decoded first, then the body and
+// finally the route parameters, so the route parameters take precedence. The body is decoded
+// conforming with its content type, or the query parameter contentType containing the mime type:
+//
+//	application/json, */*+json            json
+//	application/xml, text/xml, */*+xml    xml
+//	application/x-www-form-urlencoded     form, see BindForm
+//	multipart/form-data                   form values and uploaded files, see BindUpload
+//
+// keys without a matching field are ignored in every source, query values, form and multipart bodies,
+// uploaded files and route parameters, as the json and xml decoders ignore unknown keys, use BindForm,
+// BindGetForm or BindUpload to reject them. Route parameters match the fields by their "param" tag,
+// "formam" tag, name or lower case name. Errors are *Error with status
+// http.StatusBadRequest, http.StatusRequestEntityTooLarge or http.StatusUnsupportedMediaType wrapping
+// a *BindError, see Binder for the settings, multipart bodies are limited by the Uploader settings
+func (c *Context) Bind(target interface{}) error {
+	binder := GetBinder(c.Registry)
+
+	query := c.Request.URL.Query()
+	if len(query) > 0 {
+		if err := decodeForm(query, target, true); err != nil {
+			return bindError(http.StatusBadRequest, "query", err)
+		}
+	}
+
+	contentType := c.Request.Header.Get("Content-Type")
+	if parameter := binder.contentTypeParameter(); parameter != "-" && query.Get(parameter) != "" {
+		contentType = query.Get(parameter)
+	}
+	if err := c.bindBody(binder, contentType, target); err != nil {
+		return err
+	}
+
+	return c.bindRoute(target)
+}
+
+func (c *Context) bindBody(binder *Binder, contentType string, target interface{}) error {
+	if c.body == nil && !c.bodyReady {
+		return nil
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "multipart/form-data" {
+		if contentType != c.Request.Header.Get("Content-Type") {
+			// the content type was overridden by the query, multipart reads the boundary from the header
+			c.Request.Header.Set("Content-Type", contentType)
+		}
+		// the parts are streamed with the limits of the Uploader
+		return c.bindUpload(target, true)
+	}
+
+	if limit := binder.maxBodySize(); limit > 0 {
+		if c.bodyReady {
+			if int64(len(c.bodyBytes)) > limit {
+				return bindError(http.StatusRequestEntityTooLarge, "body", &http.MaxBytesError{Limit: limit})
+			}
+		} else {
+			c.body = http.MaxBytesReader(c.Response, c.body, limit)
+		}
+	}
+
+	body, err := c.GetBodyBytes()
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return bindError(http.StatusRequestEntityTooLarge, "body", err)
+		}
+		return bindError(http.StatusBadRequest, "body", err)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return bindError(http.StatusUnsupportedMediaType, "body", fmt.Errorf("invalid content type %q: %w", contentType, err))
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		err = json.Unmarshal(body, target)
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		err = xml.Unmarshal(body, target)
+	case mediaType == "application/x-www-form-urlencoded":
+		var values url.Values
+		if values, err = url.ParseQuery(string(body)); err == nil {
+			err = decodeForm(values, target, true)
+		}
+	default:
+		return bindError(http.StatusUnsupportedMediaType, "body", fmt.Errorf("unsupported content type %q", mediaType))
+	}
+
+	if err != nil {
+		return bindError(http.StatusBadRequest, "body", err)
+	}
+	return nil
+}
+
+// bindRoute decodes the route parameters matching the fields of the target
+func (c *Context) bindRoute(target interface{}) error {
+	// the parameters of a context not dispatched by the router have no matched route
+	if !c.Routed {
+		return nil
+	}
+
+	value := reflect.ValueOf(target)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	values := url.Values{}
+	typ := value.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		for _, name := range []string{field.Tag.Get("param"), field.Tag.Get(TAG_NAME), field.Name, strings.ToLower(field.Name)} {
+			if name == "" || name == "-" {
+				continue
+			}
+			if param := c.Parameters.ByName(name); param != "" {
+				values.Set(field.Name, param)
+				break
+			}
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+	if err := decodeForm(values, target, true); err != nil {
+		return bindError(http.StatusBadRequest, "route", err)
+	}
+	return nil
+}
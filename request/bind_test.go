@@ -0,0 +1,143 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package request
+
+import (
+	"bytes"
+	"errors"
+	"github.com/CloudyKit/framework/container"
+	"github.com/CloudyKit/router"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type bindTarget struct {
+	ID     int    `param:"id" json:"-" xml:"-"`
+	Name   string `json:"name" xml:"name"`
+	Age    int    `json:"age" xml:"age"`
+	Page   int    `json:"-" xml:"-"`
+	Labels []string
+}
+
+// bind dispatches the request through a router matching /users/:id and binds it into a new target
+func bind(t *testing.T, registry *container.Registry, r *http.Request) (*bindTarget, error) {
+	t.Helper()
+	var (
+		target = new(bindTarget)
+		err    error
+	)
+
+	rt := router.New()
+	rt.AddRoute(r.Method, "/users/:id", func(w http.ResponseWriter, r *http.Request, parameter router.Parameter) {
+		registry := registry.Fork()
+		defer registry.Dispose()
+		DispatchNext(&Context{Routed: true}, "bind", w, r, parameter, registry, []Handler{HandlerFunc(func(c *Context) {
+			err = c.Bind(target)
+		})})
+	})
+	rt.ServeHTTP(httptest.NewRecorder(), r)
+	return target, err
+}
+
+func newBindRequest(method, target, contentType, body string) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	return r
+}
+
+func TestContext_Bind(t *testing.T) {
+	registry := container.New()
+	defer registry.Dispose()
+
+	var multipartBody bytes.Buffer
+	writer := multipart.NewWriter(&multipartBody)
+	_ = writer.WriteField("Name", "multipart")
+	_ = writer.WriteField("Age", "40")
+	_ = writer.WriteField("Nickname", "unknown")
+	part, _ := writer.CreateFormFile("avatar", "avatar.txt")
+	_, _ = part.Write([]byte("unknown file"))
+	_ = writer.Close()
+
+	for _, test := range []struct {
+		name    string
+		request *http.Request
+		want    bindTarget
+	}{
+		{"json", newBindRequest("POST", "/users/7?Page=2", "application/json; charset=utf-8", `{"name":"json","age":20}`), bindTarget{ID: 7, Name: "json", Age: 20, Page: 2}},
+		{"vendor json", newBindRequest("PUT", "/users/7", "application/vnd.api+json", `{"name":"vendor"}`), bindTarget{ID: 7, Name: "vendor"}},
+		{"xml", newBindRequest("POST", "/users/7", "text/xml", `<user><name>xml</name><age>30</age></user>`), bindTarget{ID: 7, Name: "xml", Age: 30}},
+		{"form", newBindRequest("POST", "/users/7?unknown=1", "application/x-www-form-urlencoded", `Name=form&Labels[0]=a&Labels[1]=b&Nickname=unknown`), bindTarget{ID: 7, Name: "form", Labels: []string{"a", "b"}}},
+		{"multipart", newBindRequest("POST", "/users/7", writer.FormDataContentType(), multipartBody.String()), bindTarget{ID: 7, Name: "multipart", Age: 40}},
+		{"query override", newBindRequest("POST", "/users/7?contentType=application/json", "text/plain", `{"name":"override"}`), bindTarget{ID: 7, Name: "override"}},
+		{"query only", newBindRequest("GET", "/users/7?Name=query&ID=9", "", ""), bindTarget{ID: 7, Name: "query"}},
+	} {
+		target, err := bind(t, registry, test.request)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if target.ID != test.want.ID || target.Name != test.want.Name || target.Age != test.want.Age || target.Page != test.want.Page || strings.Join(target.Labels, ",") != strings.Join(test.want.Labels, ",") {
+			t.Errorf("%s: expected %+v, got %+v", test.name, test.want, *target)
+		}
+	}
+}
+
+func TestContext_BindErrors(t *testing.T) {
+	registry := container.New()
+	defer registry.Dispose()
+	registry.WithValues(&Binder{MaxBodySize: 32})
+
+	for _, test := range []struct {
+		name    string
+		request *http.Request
+		status  int
+		source  string
+		field   string
+	}{
+		{"type", newBindRequest("POST", "/users/7", "application/json", `{"age":"old"}`), http.StatusBadRequest, "body", "age"},
+		{"syntax", newBindRequest("POST", "/users/7", "application/json", `{"age":`), http.StatusBadRequest, "body", ""},
+		{"form", newBindRequest("POST", "/users/7", "application/x-www-form-urlencoded", `Age=x`), http.StatusBadRequest, "body", "Age"},
+		{"query", newBindRequest("GET", "/users/7?Age=x", "", ""), http.StatusBadRequest, "query", "Age"},
+		{"route", newBindRequest("GET", "/users/abc", "", ""), http.StatusBadRequest, "route", "ID"},
+		{"too large", newBindRequest("POST", "/users/7", "application/json", `{"name":"`+strings.Repeat("x", 64)+`"}`), http.StatusRequestEntityTooLarge, "body", ""},
+		{"media type", newBindRequest("POST", "/users/7", "text/csv", `a,b`), http.StatusUnsupportedMediaType, "body", ""},
+	} {
+		_, err := bind(t, registry, test.request)
+		if status := StatusOf(err); status != test.status {
+			t.Errorf("%s: expected status %d, got %d (%v)", test.name, test.status, status, err)
+		}
+		var bindErr *BindError
+		if !errors.As(err, &bindErr) {
+			t.Errorf("%s: expected a BindError, got %v", test.name, err)
+			continue
+		}
+		if bindErr.Source != test.source || bindErr.Field != test.field {
+			t.Errorf("%s: expected source %q field %q, got %q %q", test.name, test.source, test.field, bindErr.Source, bindErr.Field)
+		}
+	}
+}
@@ -55,6 +55,7 @@ type Context struct {
 
 	Response   http.ResponseWriter // Response Writer passed by the router
 	Parameters router.Parameter    // Route Registry passed by the router
+	Routed     bool                // Routed reports if Parameters were matched by the router, the parameters of fallback and middleware requests are empty
 	body       io.ReadCloser
 	bodyBytes  []byte
 	bodyReady  bool
@@ -26,7 +26,8 @@ import (
 	"encoding/json"
 )
 
-// BindGetForm decodes the request url values into target
+// BindGetForm decodes the request url values into target, values without a matching field are rejected,
+// see Context.Bind
 func (c *Context) BindGetForm(target interface{}) error {
 	c.Request.Body = c.GetBodyReader()
 	if c.Request.Form == nil {
@@ -35,7 +36,8 @@ func (c *Context) BindGetForm(target interface{}) error {
 	return formamDecoder(c.Request.Form, target)
 }
 
-// BindForm decodes request post data into target
+// BindForm decodes request post data into target, values without a matching field are rejected,
+// see Context.Bind
 func (c *Context) BindForm(target interface{}) error {
 	c.Request.Body = c.GetBodyReader()
 	if c.Request.PostForm == nil {
@@ -48,9 +50,3 @@ func (c *Context) BindForm(target interface{}) error {
 func (c *Context) BindJSON(target interface{}) error {
 	return json.NewDecoder(c.GetBodyReader()).Decode(target)
 }
-
-// todo: add a generic bind func which will decode values conforming with
-// the request content-type or a query string contentType containing the mime type.
-// func (ctx *Context) Bind(target interface{}) error {
-//	return ctx.BindForm(target)
-// }
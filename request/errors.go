@@ -0,0 +1,128 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package request
+
+import (
+	"errors"
+	"fmt"
+	"github.com/CloudyKit/framework/container"
+	"net/http"
+	"reflect"
+	"runtime/debug"
+)
+
+var ErrorHandlerType = reflect.TypeOf((*ErrorHandler)(nil)).Elem()
+
+// GetErrorHandler gets the ErrorHandler from the Registry context
+func GetErrorHandler(cdi *container.Registry) ErrorHandler {
+	handler, _ := cdi.LoadType(ErrorHandlerType).(ErrorHandler)
+	return handler
+}
+
+// ErrorHandler is responsible to convert an error returned or raised by a handler into a response
+type ErrorHandler interface {
+	HandleError(c *Context, err error)
+}
+
+// ErrorHandlerFunc func implementing ErrorHandler interface
+type ErrorHandlerFunc func(c *Context, err error)
+
+func (fn ErrorHandlerFunc) HandleError(c *Context, err error) {
+	fn(c, err)
+}
+
+// Error is an error carrying the http status code that should be sent to the client,
+// Message is exposed to the client while Err is kept for logging
+type Error struct {
+	Status  int
+	Message string
+	Err     error
+}
+
+// NewError creates an Error with the status code and a message exposed to the client
+func NewError(status int, message string) *Error {
+	return &Error{Status: status, Message: message}
+}
+
+// WrapError creates an Error with the status code caused by err
+func WrapError(status int, err error) *Error {
+	return &Error{Status: status, Err: err}
+}
+
+func (e *Error) Error() string {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.Status)
+	}
+	if e.Err != nil {
+		return message + ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// PanicError wraps a value recovered from a panic in the request flow
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// NewPanicError creates a PanicError capturing the current stack, should be called from the recovering func
+func NewPanicError(value interface{}) *PanicError {
+	return &PanicError{Value: value, Stack: debug.Stack()}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// StatusOf returns the status code carried by err, errors without status are reported as http.StatusInternalServerError
+func StatusOf(err error) int {
+	var statusErr *Error
+	if errors.As(err, &statusErr) && statusErr.Status != 0 {
+		return statusErr.Status
+	}
+	return http.StatusInternalServerError
+}
+
+// Error sends err to the ErrorHandler available in the registry, case no handler is available
+// a plain text response is sent with the status of the error
+func (c *Context) Error(err error) {
+	if err == nil {
+		return
+	}
+	if handler := GetErrorHandler(c.Registry); handler != nil {
+		handler.HandleError(c, err)
+		return
+	}
+	status := StatusOf(err)
+	http.Error(c.Response, http.StatusText(status), status)
+}
@@ -23,9 +23,12 @@
 package request
 
 import (
+	"fmt"
 	"github.com/CloudyKit/framework/container"
 	"github.com/CloudyKit/router"
 	"net/http"
+	"reflect"
+	"runtime"
 )
 
 // HandlerFunc func implementing Handler interface
@@ -51,7 +54,8 @@ func DispatchNext(context *Context, name string, writer http.ResponseWriter, req
 	context.Parameters = parameter
 	context.Registry = registry
 	context.handlers = handlers
-	if context.Request.Body != nil {
+	// the request is nil when the flow is dispatched without an http request, ex: TestContext_Advance
+	if request != nil && request.Body != nil {
 		context.body = context.Request.Body
 		context.Request.Body = context.GetBodyReader()
 	}
@@ -61,3 +65,26 @@ func DispatchNext(context *Context, name string, writer http.ResponseWriter, req
 
 	return context.Next()
 }
+
+// WrapHandlers wraps the handlers remaining in the request flow, ex: measure the time spent by each handler
+func (c *Context) WrapHandlers(wrap func(Handler) Handler) {
+	handlers := make([]Handler, len(c.handlers))
+	for i, handler := range c.handlers {
+		handlers[i] = wrap(handler)
+	}
+	c.handlers = handlers
+}
+
+// HandlerName returns a readable name for the handler, the String result, the func name or the type name,
+// ex: in the route listing and in the tracing spans
+func HandlerName(handler Handler) string {
+	if stringer, ok := handler.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	if value := reflect.ValueOf(handler); value.Kind() == reflect.Func {
+		if fn := runtime.FuncForPC(value.Pointer()); fn != nil {
+			return fn.Name()
+		}
+	}
+	return fmt.Sprintf("%T", handler)
+}
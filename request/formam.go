@@ -51,10 +51,41 @@ type decoder struct {
 	field string
 	value string
 	index int
+
+	file *UploadedFile // file set in the current path, see decodeFiles
+}
+
+// formPathError an error decoding the value of a form path
+type formPathError struct {
+	Path string
+	Err  error
+}
+
+func (e *formPathError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *formPathError) Unwrap() error {
+	return e.Err
+}
+
+// fieldNotFoundError the form path has no matching field in the target
+type fieldNotFoundError struct {
+	field string
+	path  string
+}
+
+func (e *fieldNotFoundError) Error() string {
+	return fmt.Sprintf("formam: not found the field \"%v\" in the path \"%v\"", e.field, e.path)
 }
 
 // Decode decodes the url.Values into a element that must be a pointer to a type provided by argument
 func formamDecoder(vs url.Values, dst interface{}) error {
+	return decodeForm(vs, dst, false)
+}
+
+// decodeForm decodes the url.Values into dst, paths without a matching field are skipped when ignoreUnknown is set
+func decodeForm(vs url.Values, dst interface{}, ignoreUnknown bool) error {
 	main := reflect.ValueOf(dst)
 	if main.Kind() != reflect.Ptr {
 		return fmt.Errorf("formam: the value passed for decode is not a pointer but a %v", main.Kind())
@@ -66,7 +97,11 @@ func formamDecoder(vs url.Values, dst interface{}) error {
 		d.value = v[0]
 		if d.value != "" {
 			if err := d.begin(); err != nil {
-				return err
+				var notFound *fieldNotFoundError
+				if ignoreUnknown && errors.As(err, &notFound) {
+					continue
+				}
+				return &formPathError{Path: k, Err: err}
 			}
 		}
 	}
@@ -191,6 +226,10 @@ func (d *decoder) end() error {
 
 // decode sets the value in the last field found by end function
 func (d *decoder) decode() error {
+	if d.file != nil {
+		return d.decodeFile()
+	}
+
 	ok, err := d.unmarshalText(d.curr)
 	if ok {
 		return err
@@ -304,7 +343,59 @@ func (d *decoder) findField() error {
 		return nil
 	}
 
-	return fmt.Errorf("formam: not found the field \"%v\" in the path \"%v\"", d.field, d.path)
+	return &fieldNotFoundError{field: d.field, path: d.path}
+}
+
+var (
+	uploadedFilePtrType   = reflect.TypeOf(&UploadedFile{})
+	uploadedFileSliceType = reflect.TypeOf([]*UploadedFile{})
+)
+
+// decodeFiles sets the uploaded files into dst, the paths are the form field names, ex: avatar, photos[0], profile.avatar,
+// many files in the same path are appended to a []*UploadedFile field, paths without a matching field are skipped
+// when ignoreUnknown is set
+func decodeFiles(files map[string][]*UploadedFile, dst interface{}, ignoreUnknown bool) error {
+	main := reflect.ValueOf(dst)
+	if main.Kind() != reflect.Ptr {
+		return fmt.Errorf("formam: the value passed for decode is not a pointer but a %v", main.Kind())
+	}
+	d := &decoder{main: main.Elem()}
+	for k, fs := range files {
+		for _, file := range fs {
+			d.path = k
+			d.field = k
+			d.value = file.Filename
+			d.file = file
+			if err := d.begin(); err != nil {
+				var notFound *fieldNotFoundError
+				if ignoreUnknown && errors.As(err, &notFound) {
+					break
+				}
+				return &formPathError{Path: k, Err: err}
+			}
+		}
+	}
+	return nil
+}
+
+// decodeFile sets the uploaded file in the last field found by end function
+func (d *decoder) decodeFile() error {
+	switch d.curr.Type() {
+	case uploadedFilePtrType:
+		d.curr.Set(reflect.ValueOf(d.file))
+	case uploadedFileSliceType:
+		if d.index == -1 {
+			d.curr.Set(reflect.Append(d.curr, reflect.ValueOf(d.file)))
+			return nil
+		}
+		if d.curr.Len() <= d.index {
+			d.expandSlice()
+		}
+		d.curr.Index(d.index).Set(reflect.ValueOf(d.file))
+	default:
+		return fmt.Errorf("formam: the field \"%v\" in path \"%v\" can't hold an uploaded file", d.field, d.path)
+	}
+	return nil
 }
 
 // expandSlice expands the length and capacity of the current slice
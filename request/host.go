@@ -0,0 +1,59 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package request
+
+import (
+	"context"
+	"net/http"
+)
+
+type hostParametersKey struct{}
+
+// HostParameters the parameters matched in the host pattern of the route, ex: {tenant}.example.com
+type HostParameters map[string]string
+
+// ByName returns the host parameter with name
+func (params HostParameters) ByName(name string) string {
+	return params[name]
+}
+
+// WithHostParameters returns a shallow copy of r carrying the host parameters
+func WithHostParameters(r *http.Request, params HostParameters) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), hostParametersKey{}, params))
+}
+
+// GetHostParameters returns the host parameters carried by r
+func GetHostParameters(r *http.Request) HostParameters {
+	params, _ := r.Context().Value(hostParametersKey{}).(HostParameters)
+	return params
+}
+
+// HostParameters returns the parameters matched in the host of the route
+func (c *Context) HostParameters() HostParameters {
+	return GetHostParameters(c.Request)
+}
+
+// GetHostParameter returns a parameter from the host route, see GetURLParameter
+func (c *Context) GetHostParameter(name string) string {
+	return c.HostParameters().ByName(name)
+}
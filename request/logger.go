@@ -0,0 +1,66 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package request
+
+import (
+	"context"
+	"github.com/CloudyKit/framework/container"
+	"log/slog"
+	"net/http"
+	"reflect"
+)
+
+var LoggerType = reflect.TypeOf((*slog.Logger)(nil))
+
+// GetLogger returns the logger provided by the registry or slog.Default
+func GetLogger(registry *container.Registry) *slog.Logger {
+	if registry != nil {
+		if logger, _ := registry.LoadType(LoggerType).(*slog.Logger); logger != nil {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+// Logger returns the logger of the request, ex: a logger enriched with the request id
+func (c *Context) Logger() *slog.Logger {
+	return GetLogger(c.Registry)
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a shallow copy of r carrying the request id
+func WithRequestID(r *http.Request, id string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id))
+}
+
+// GetRequestID returns the request id carried by ctx
+func GetRequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestID returns the id of the request
+func (c *Context) RequestID() string {
+	return GetRequestID(c.Request.Context())
+}
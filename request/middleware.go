@@ -0,0 +1,73 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package request
+
+import (
+	"context"
+	"net/http"
+)
+
+// Middleware standard net/http middleware signature
+type Middleware func(http.Handler) http.Handler
+
+type middlewareContextKey struct{}
+
+// WrapMiddleware adapts a standard net/http middleware into a Handler, the middleware is built once
+// and the next handler passed to it advances the request flow with Context.Next, the ResponseWriter
+// and *http.Request received by the next handler are propagated into the Context, case the middleware
+// doesn't call the next handler the request flow stops there.
+func WrapMiddleware(middleware func(http.Handler) http.Handler) Handler {
+	return &middlewareHandler{handler: middleware(http.HandlerFunc(advanceMiddleware))}
+}
+
+// WrapMiddlewares adapts multiple standard middlewares, see WrapMiddleware
+func WrapMiddlewares(middlewares ...func(http.Handler) http.Handler) []Handler {
+	handlers := make([]Handler, len(middlewares))
+	for i, middleware := range middlewares {
+		handlers[i] = WrapMiddleware(middleware)
+	}
+	return handlers
+}
+
+type middlewareHandler struct {
+	handler http.Handler
+}
+
+func (m *middlewareHandler) Handle(c *Context) {
+	response, request := c.Response, c.Request
+	m.handler.ServeHTTP(response, request.WithContext(context.WithValue(request.Context(), middlewareContextKey{}, c)))
+	// restores the response and the request, handlers finalizing after Next must not
+	// write using a writer already finalized by the middleware
+	c.Response, c.Request = response, request
+}
+
+// advanceMiddleware is the next handler passed to wrapped middlewares
+func advanceMiddleware(rw http.ResponseWriter, r *http.Request) {
+	c, _ := r.Context().Value(middlewareContextKey{}).(*Context)
+	if c == nil {
+		panic("request: wrapped middleware called next with a request without the request.Context")
+	}
+	c.Response = rw
+	c.Request = r
+	_ = c.Next()
+}
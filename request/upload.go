@@ -0,0 +1,411 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package request
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/CloudyKit/framework/container"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+const (
+	DefaultMaxFileSize   = 32 << 20  // DefaultMaxFileSize max size of an uploaded file
+	DefaultMaxTotalSize  = 128 << 20 // DefaultMaxTotalSize max size of all the files uploaded in a request
+	DefaultMaxValuesSize = 1 << 20   // DefaultMaxValuesSize max size of the non file values of a multipart request
+	DefaultMaxFiles      = 32        // DefaultMaxFiles max number of files uploaded in a request
+)
+
+var (
+	ErrFileTooLarge   = errors.New("request: uploaded file exceeds the size limit")
+	ErrUploadTooLarge = errors.New("request: uploaded files exceed the total size limit")
+	ErrValuesTooLarge = errors.New("request: multipart values exceed the size limit")
+	ErrTooManyFiles   = errors.New("request: too many uploaded files")
+	ErrFileType       = errors.New("request: file type not allowed")
+)
+
+var (
+	UploaderType = reflect.TypeOf((*Uploader)(nil))
+	UploadsType  = reflect.TypeOf((*Uploads)(nil))
+)
+
+// DefaultUploader used by Context.Upload when the registry doesn't provide an Uploader
+var DefaultUploader = &Uploader{}
+
+// GetUploader gets the Uploader from the Registry context, DefaultUploader is returned when the registry has no uploader
+func GetUploader(cdi *container.Registry) *Uploader {
+	if cdi != nil {
+		if uploader, _ := cdi.LoadType(UploaderType).(*Uploader); uploader != nil {
+			return uploader
+		}
+	}
+	return DefaultUploader
+}
+
+// Storage stores the uploaded files, see DiskStorage
+type Storage interface {
+	// Save stores the content of file read from r, the returned key is used to open and remove the file
+	Save(ctx context.Context, file *UploadedFile, r io.Reader) (key string, err error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Remove(ctx context.Context, key string) error
+}
+
+// DiskStorage stores the uploaded files in Dir, the keys are the file paths
+type DiskStorage struct {
+	Dir string // Dir defaults to os.TempDir
+}
+
+func (storage *DiskStorage) Save(_ context.Context, file *UploadedFile, r io.Reader) (string, error) {
+	dir := storage.Dir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	ext := filepath.Ext(file.Filename)
+	if len(ext) > 16 || strings.ContainsAny(ext, `*/\`) {
+		ext = ""
+	}
+	f, err := os.CreateTemp(dir, "upload-*"+ext)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+func (storage *DiskStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	return os.Open(key)
+}
+
+func (storage *DiskStorage) Remove(_ context.Context, key string) error {
+	err := os.Remove(key)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Uploader settings of Context.Upload, provide an uploader in the registry to change the settings,
+// ex: registry.WithValues(&request.Uploader{AllowedTypes: []string{"image/*"}})
+type Uploader struct {
+	MaxFileSize   int64    // MaxFileSize defaults to DefaultMaxFileSize
+	MaxTotalSize  int64    // MaxTotalSize defaults to DefaultMaxTotalSize
+	MaxValuesSize int64    // MaxValuesSize defaults to DefaultMaxValuesSize
+	MaxFiles      int      // MaxFiles defaults to DefaultMaxFiles
+	AllowedTypes  []string // AllowedTypes mime types sniffed from the content, ex: "image/png", "image/*", all types are allowed when empty
+	Storage       Storage  // Storage defaults to DiskStorage in os.TempDir
+}
+
+func orDefault(value, def int64) int64 {
+	if value <= 0 {
+		return def
+	}
+	return value
+}
+
+func (uploader *Uploader) storage() Storage {
+	if uploader.Storage == nil {
+		return &DiskStorage{}
+	}
+	return uploader.Storage
+}
+
+// allowed reports if the sniffed mediaType is allowed
+func (uploader *Uploader) allowed(mediaType string) bool {
+	if len(uploader.AllowedTypes) == 0 {
+		return true
+	}
+	for _, allowed := range uploader.AllowedTypes {
+		if allowed == mediaType || strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mediaType, allowed[:len(allowed)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// UploadedFile a file part of a multipart request saved in the storage
+type UploadedFile struct {
+	Field       string               // Field form field name
+	Filename    string               // Filename base name of the file sent by the client
+	ContentType string               // ContentType mime type sniffed from the content, see http.DetectContentType
+	Size        int64                // Size in bytes
+	Key         string               // Key of the file in the storage
+	Header      textproto.MIMEHeader // Header of the part, the client content type is in Header.Get("Content-Type")
+
+	storage Storage
+	kept    bool
+}
+
+// Open opens the stored file
+func (file *UploadedFile) Open(ctx context.Context) (io.ReadCloser, error) {
+	return file.storage.Open(ctx, file.Key)
+}
+
+// Keep keeps the file in the storage when the request registry is disposed
+func (file *UploadedFile) Keep() {
+	file.kept = true
+}
+
+// MoveTo moves the stored file to path and keeps it, see Keep
+func (file *UploadedFile) MoveTo(ctx context.Context, path string) error {
+	if _, onDisk := file.storage.(*DiskStorage); onDisk {
+		if err := os.Rename(file.Key, path); err == nil {
+			file.Key, file.storage = path, &DiskStorage{Dir: filepath.Dir(path)}
+			file.kept = true
+			return nil
+		}
+		// falls back to copy, ex: path is in another device
+	}
+
+	src, err := file.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	_ = file.storage.Remove(ctx, file.Key)
+	file.Key, file.storage = path, &DiskStorage{Dir: filepath.Dir(path)}
+	file.kept = true
+	return nil
+}
+
+// Uploads the values and files of a multipart request, the files not kept are removed from the
+// storage when the request registry is disposed, see UploadedFile.Keep
+type Uploads struct {
+	Values url.Values
+	Files  map[string][]*UploadedFile
+}
+
+// File returns the first file of the field
+func (uploads *Uploads) File(field string) *UploadedFile {
+	if files := uploads.Files[field]; len(files) > 0 {
+		return files[0]
+	}
+	return nil
+}
+
+// Dispose removes the files not kept from the storage, see container.Disposer
+func (uploads *Uploads) Dispose() {
+	for _, files := range uploads.Files {
+		for _, file := range files {
+			if !file.kept {
+				_ = file.storage.Remove(context.Background(), file.Key)
+			}
+		}
+	}
+}
+
+// uploadReader counts the bytes of a file, failing once the file or the request limits are exceeded
+type uploadReader struct {
+	r         io.Reader
+	size      int64
+	maxSize   int64
+	remaining *int64
+}
+
+func (reader *uploadReader) Read(p []byte) (int, error) {
+	n, err := reader.r.Read(p)
+	reader.size += int64(n)
+	*reader.remaining -= int64(n)
+	switch {
+	case reader.size > reader.maxSize:
+		return n, ErrFileTooLarge
+	case *reader.remaining < 0:
+		return n, ErrUploadTooLarge
+	}
+	return n, err
+}
+
+// Upload streams the parts of a multipart/form-data request, the files are saved in the storage of the
+// Uploader provided by the registry and the values are kept in memory, the body is not buffered. The limits
+// and the allowed types of the Uploader are enforced, errors are *Error wrapping a *BindError, see GetUploader.
+// The uploads are registered in the request registry, calling Upload again returns the same uploads
+func (c *Context) Upload() (*Uploads, error) {
+	if uploads, _ := c.Registry.LoadType(UploadsType).(*Uploads); uploads != nil {
+		return uploads, nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(c.Request.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		return nil, bindError(http.StatusUnsupportedMediaType, "body", fmt.Errorf("expected multipart/form-data, got %q", c.Request.Header.Get("Content-Type")))
+	}
+	if params["boundary"] == "" {
+		return nil, bindError(http.StatusBadRequest, "body", errors.New("multipart boundary not found"))
+	}
+
+	uploader := GetUploader(c.Registry)
+	maxFileSize := orDefault(uploader.MaxFileSize, DefaultMaxFileSize)
+	remainingFiles := orDefault(uploader.MaxTotalSize, DefaultMaxTotalSize)
+	remainingValues := orDefault(uploader.MaxValuesSize, DefaultMaxValuesSize)
+	maxFiles := uploader.MaxFiles
+	if maxFiles <= 0 {
+		maxFiles = DefaultMaxFiles
+	}
+	storage := uploader.storage()
+
+	var body io.Reader
+	switch {
+	case c.bodyReady:
+		body = bytes.NewReader(c.bodyBytes)
+	case c.body != nil:
+		// the parts headers and boundaries are bounded along with the files and values
+		body = http.MaxBytesReader(c.Response, c.body, remainingFiles+remainingValues+1<<20)
+		// the body is streamed, it can't be read again
+		c.bodyReady, c.bodyBytes = true, nil
+	default:
+		return nil, bindError(http.StatusBadRequest, "body", errors.New("empty body"))
+	}
+
+	uploads := &Uploads{Values: url.Values{}, Files: map[string][]*UploadedFile{}}
+	fail := func(status int, field string, err error) (*Uploads, error) {
+		uploads.Dispose()
+		bindErr := &BindError{Source: "body", Field: field, Err: err}
+		return nil, WrapError(status, bindErr)
+	}
+
+	ctx := c.Context()
+	reader := multipart.NewReader(body, params["boundary"])
+	numFiles := 0
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return fail(http.StatusRequestEntityTooLarge, "", err)
+			}
+			return fail(http.StatusBadRequest, "", err)
+		}
+
+		field := part.FormName()
+		if field == "" {
+			continue
+		}
+
+		if part.FileName() == "" {
+			var value bytes.Buffer
+			n, err := io.Copy(&value, io.LimitReader(part, remainingValues+1))
+			if err != nil {
+				return fail(http.StatusBadRequest, field, err)
+			}
+			if remainingValues -= n; remainingValues < 0 {
+				return fail(http.StatusRequestEntityTooLarge, field, ErrValuesTooLarge)
+			}
+			uploads.Values.Add(field, value.String())
+			continue
+		}
+
+		if numFiles++; numFiles > maxFiles {
+			return fail(http.StatusRequestEntityTooLarge, field, ErrTooManyFiles)
+		}
+
+		// the content type is sniffed, the one sent by the client is not trusted
+		sniff := make([]byte, 512)
+		n, err := io.ReadFull(part, sniff)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return fail(http.StatusBadRequest, field, err)
+		}
+		sniff = sniff[:n]
+		contentType, _, _ := mime.ParseMediaType(http.DetectContentType(sniff))
+		if !uploader.allowed(contentType) {
+			return fail(http.StatusUnsupportedMediaType, field, fmt.Errorf("%w: %s", ErrFileType, contentType))
+		}
+
+		file := &UploadedFile{Field: field, Filename: part.FileName(), ContentType: contentType, Header: part.Header, storage: storage}
+		content := &uploadReader{r: io.MultiReader(bytes.NewReader(sniff), part), maxSize: maxFileSize, remaining: &remainingFiles}
+		file.Key, err = storage.Save(ctx, file, content)
+		file.Size = content.size
+		if err != nil {
+			if file.Key != "" {
+				_ = storage.Remove(ctx, file.Key)
+			}
+			switch {
+			case errors.Is(err, ErrFileTooLarge), errors.Is(err, ErrUploadTooLarge):
+				return fail(http.StatusRequestEntityTooLarge, field, err)
+			}
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return fail(http.StatusRequestEntityTooLarge, field, err)
+			}
+			return fail(http.StatusBadRequest, field, err)
+		}
+		uploads.Files[field] = append(uploads.Files[field], file)
+	}
+
+	c.Registry.WithTypeAndValue(UploadsType, uploads)
+	return uploads, nil
+}
+
+// BindUpload streams the multipart request and binds the values and files into target, fields of
+// type *UploadedFile and []*UploadedFile are set from the files, values and files without a matching
+// field are rejected, see Context.Upload and Context.Bind
+func (c *Context) BindUpload(target interface{}) error {
+	return c.bindUpload(target, false)
+}
+
+func (c *Context) bindUpload(target interface{}, ignoreUnknown bool) error {
+	uploads, err := c.Upload()
+	if err != nil {
+		return err
+	}
+	if err := decodeForm(uploads.Values, target, ignoreUnknown); err != nil {
+		return bindError(http.StatusBadRequest, "body", err)
+	}
+	if err := decodeFiles(uploads.Files, target, ignoreUnknown); err != nil {
+		return bindError(http.StatusBadRequest, "body", err)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package request
+
+import (
+	"bytes"
+	"errors"
+	"github.com/CloudyKit/framework/container"
+	"github.com/CloudyKit/router"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var pngContent = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 100)...)
+
+type uploadPart struct {
+	field, filename string
+	content         []byte
+}
+
+func newUploadRequest(parts ...uploadPart) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, part := range parts {
+		if part.filename == "" {
+			_ = writer.WriteField(part.field, string(part.content))
+			continue
+		}
+		w, _ := writer.CreateFormFile(part.field, part.filename)
+		_, _ = w.Write(part.content)
+	}
+	_ = writer.Close()
+
+	r := httptest.NewRequest("POST", "/upload", &body)
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	return r
+}
+
+// upload dispatches r with a forked registry, handler runs before the registry is disposed
+func upload(registry *container.Registry, r *http.Request, handler func(c *Context)) {
+	registry = registry.Fork()
+	defer registry.Dispose()
+	DispatchNext(new(Context), "upload", httptest.NewRecorder(), r, router.Parameter{}, registry, []Handler{HandlerFunc(handler)})
+}
+
+type profileForm struct {
+	Name   string
+	Avatar *UploadedFile
+	Photos []*UploadedFile
+}
+
+func storedFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestContext_BindUpload(t *testing.T) {
+	dir := t.TempDir()
+	registry := container.New()
+	defer registry.Dispose()
+	registry.WithValues(&Uploader{Storage: &DiskStorage{Dir: dir}})
+
+	keptPath := filepath.Join(t.TempDir(), "avatar.png")
+	r := newUploadRequest(
+		uploadPart{field: "Name", content: []byte("gopher")},
+		uploadPart{field: "Avatar", filename: "../../me.png", content: pngContent},
+		uploadPart{field: "Photos", filename: "a.txt", content: []byte("first photo")},
+		uploadPart{field: "Photos", filename: "b.txt", content: []byte("second photo")},
+	)
+
+	upload(registry, r, func(c *Context) {
+		form := new(profileForm)
+		if err := c.Bind(form); err != nil {
+			t.Fatal(err)
+		}
+
+		if form.Name != "gopher" || form.Avatar == nil || len(form.Photos) != 2 {
+			t.Fatalf("unexpected form %+v", form)
+		}
+		if form.Avatar.Filename != "me.png" || form.Avatar.ContentType != "image/png" || form.Avatar.Size != int64(len(pngContent)) {
+			t.Errorf("unexpected avatar %+v", form.Avatar)
+		}
+		if form.Photos[1].ContentType != "text/plain" {
+			t.Errorf("unexpected sniffed type %s", form.Photos[1].ContentType)
+		}
+
+		reader, err := form.Photos[1].Open(c.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(reader)
+		reader.Close()
+		if string(content) != "second photo" {
+			t.Errorf("unexpected content %q", content)
+		}
+
+		if files := storedFiles(t, dir); len(files) != 3 {
+			t.Errorf("expected 3 stored files, got %v", files)
+		}
+		if err := form.Avatar.MoveTo(c.Context(), keptPath); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	// the files not kept are removed with the request registry
+	if files := storedFiles(t, dir); len(files) != 0 {
+		t.Errorf("expected the temporary files to be removed, got %v", files)
+	}
+	if _, err := os.Stat(keptPath); err != nil {
+		t.Errorf("moved file should be kept: %v", err)
+	}
+
+	r = newUploadRequest(uploadPart{field: "Cover", filename: "cover.txt", content: []byte("cover")})
+	upload(registry, r, func(c *Context) {
+		var bindErr *BindError
+		if err := c.BindUpload(new(profileForm)); !errors.As(err, &bindErr) || bindErr.Field != "Cover" {
+			t.Errorf("BindUpload should reject files without a matching field, got %v", err)
+		}
+	})
+}
+
+func TestContext_UploadLimits(t *testing.T) {
+	dir := t.TempDir()
+	registry := container.New()
+	defer registry.Dispose()
+	registry.WithValues(&Uploader{
+		MaxFileSize:   200,
+		MaxTotalSize:  300,
+		MaxValuesSize: 16,
+		MaxFiles:      3,
+		AllowedTypes:  []string{"image/*"},
+		Storage:       &DiskStorage{Dir: dir},
+	})
+
+	for _, test := range []struct {
+		name   string
+		parts  []uploadPart
+		status int
+		err    error
+		field  string
+	}{
+		{"file size", []uploadPart{{field: "a", filename: "a.png", content: append(pngContent, make([]byte, 200)...)}}, http.StatusRequestEntityTooLarge, ErrFileTooLarge, "a"},
+		{"total size", []uploadPart{{field: "a", filename: "a.png", content: pngContent}, {field: "b", filename: "b.png", content: pngContent}, {field: "c", filename: "c.png", content: pngContent}}, http.StatusRequestEntityTooLarge, ErrUploadTooLarge, "c"},
+		{"values size", []uploadPart{{field: "name", content: []byte(strings.Repeat("x", 17))}}, http.StatusRequestEntityTooLarge, ErrValuesTooLarge, "name"},
+		{"file count", []uploadPart{{field: "a", filename: "a.png", content: pngContent[:8]}, {field: "a", filename: "a.png", content: pngContent[:8]}, {field: "a", filename: "a.png", content: pngContent[:8]}, {field: "a", filename: "a.png", content: pngContent[:8]}}, http.StatusRequestEntityTooLarge, ErrTooManyFiles, "a"},
+		{"sniffed type", []uploadPart{{field: "a", filename: "a.png", content: []byte("not an image")}}, http.StatusUnsupportedMediaType, ErrFileType, "a"},
+	} {
+		upload(registry, newUploadRequest(test.parts...), func(c *Context) {
+			_, err := c.Upload()
+			if status := StatusOf(err); status != test.status {
+				t.Errorf("%s: expected status %d, got %d (%v)", test.name, test.status, status, err)
+			}
+			var bindErr *BindError
+			if !errors.As(err, &bindErr) || bindErr.Field != test.field || !errors.Is(err, test.err) {
+				t.Errorf("%s: expected %v in field %s, got %v", test.name, test.err, test.field, err)
+			}
+			if files := storedFiles(t, dir); len(files) != 0 {
+				t.Errorf("%s: files of a failed upload should be removed, got %v", test.name, files)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/CloudyKit/framework/app"
+	"github.com/CloudyKit/framework/request"
+	"net/http"
+)
+
+// Component provides a scheduler started and stopped with the kernel, see app.Kernel.Start,
+// components add their jobs with GetScheduler
+type Component struct {
+	Scheduler  *Scheduler // Scheduler defaults to a scheduler forking the kernel registry
+	StatusPath string     // StatusPath serves the job statuses as json when set, ex: /jobs
+	// StatusFilters run before the status endpoint after the kernel filters, the job names and their last
+	// errors are exposed, so the endpoint should be protected, ex: an authentication filter
+	StatusFilters []request.Handler
+}
+
+func (component *Component) Bootstrap(a *app.Kernel) {
+	if component.Scheduler == nil {
+		component.Scheduler = New(a.Registry)
+	} else if component.Scheduler.Registry == nil {
+		component.Scheduler.Registry = a.Registry
+	}
+
+	a.Registry.WithTypeAndValue(SchedulerType, component.Scheduler)
+
+	if component.StatusPath != "" {
+		a.MountFiltered(component.StatusPath, http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_ = json.NewEncoder(rw).Encode(component.Scheduler.Statuses())
+		}), component.StatusFilters...)
+	}
+}
+
+func (component *Component) Start(ctx context.Context) error {
+	return component.Scheduler.Start(ctx)
+}
+
+func (component *Component) Stop(ctx context.Context) error {
+	return component.Scheduler.Stop(ctx)
+}
@@ -0,0 +1,220 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes the next run of a job
+type Schedule interface {
+	// Next returns the first run time after t, the zero time means the job will not run again
+	Next(t time.Time) time.Time
+}
+
+// Every returns a schedule running each interval
+func Every(interval time.Duration) Schedule {
+	if interval <= 0 {
+		panic(fmt.Errorf("scheduler: invalid interval %s", interval))
+	}
+	return everySchedule(interval)
+}
+
+type everySchedule time.Duration
+
+func (interval everySchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(interval))
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+// cronSchedule the fields of a cron expression as bit sets
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+	location                      *time.Location
+}
+
+// ParseCron parses a standard five fields cron expression "minute hour day-of-month month day-of-week",
+// fields accept *, lists, ranges, steps and month and weekday names, ex: "*/15 9-18 * * mon-fri", the
+// macros @yearly, @monthly, @weekly, @daily, @hourly and "@every <duration>" are also accepted.
+// The times are computed in location, nil location uses time.Local
+func ParseCron(expression string, location *time.Location) (Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	if strings.HasPrefix(expression, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(expression[len("@every "):]))
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("scheduler: invalid interval in %q", expression)
+		}
+		return Every(interval), nil
+	}
+	if macro, found := cronMacros[expression]; found {
+		expression = macro
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("scheduler: cron expression %q must have %d fields", expression, len(cronFields))
+	}
+
+	if location == nil {
+		location = time.Local
+	}
+	schedule := &cronSchedule{location: location}
+	bits := []*uint64{&schedule.minute, &schedule.hour, &schedule.dom, &schedule.month, &schedule.dow}
+	for i, field := range fields {
+		var err error
+		if *bits[i], err = cronFields[i].parse(field); err != nil {
+			return nil, fmt.Errorf("scheduler: cron expression %q: %w", expression, err)
+		}
+	}
+
+	schedule.domStar = strings.HasPrefix(fields[2], "*")
+	schedule.dowStar = strings.HasPrefix(fields[4], "*")
+	// sunday can be written as 7
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+	return schedule, nil
+}
+
+// MustParseCron same as ParseCron but panics on invalid expressions
+func MustParseCron(expression string, location *time.Location) Schedule {
+	schedule, err := ParseCron(expression, location)
+	if err != nil {
+		panic(err)
+	}
+	return schedule
+}
+
+func (field cronField) parse(expression string) (bits uint64, err error) {
+	for _, part := range strings.Split(expression, ",") {
+		start, end, step := field.min, field.max, 1
+
+		rangeExpression := part
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			rangeExpression = part[:i]
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field %q", field.name, part)
+			}
+		}
+
+		if rangeExpression != "*" {
+			bounds := strings.SplitN(rangeExpression, "-", 2)
+			if start, err = field.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			end = start
+			if len(bounds) == 2 {
+				if end, err = field.value(bounds[1]); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// a/n runs from a to the max value
+				end = field.max
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range in %s field %q", field.name, part)
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+func (field cronField) value(expression string) (int, error) {
+	if value, found := field.names[strings.ToLower(expression)]; found {
+		return value, nil
+	}
+	value, err := strconv.Atoi(expression)
+	if err != nil || value < field.min || value > field.max {
+		return 0, fmt.Errorf("invalid %s %q", field.name, expression)
+	}
+	return value, nil
+}
+
+func (schedule *cronSchedule) Next(t time.Time) time.Time {
+	t = t.In(schedule.location)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, schedule.location)
+
+	// an expression matching only impossible dates, ex: 30 of february, never runs
+	limit := t.Year() + 5
+	for t.Year() <= limit {
+		switch {
+		case schedule.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, schedule.location)
+		case !schedule.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, schedule.location)
+		case schedule.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, schedule.location)
+		case schedule.minute&(1<<uint(t.Minute())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, schedule.location)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches as in cron, when both day fields are restricted the day matches either of them
+func (schedule *cronSchedule) dayMatches(t time.Time) bool {
+	dom := schedule.dom&(1<<uint(t.Day())) != 0
+	dow := schedule.dow&(1<<uint(t.Weekday())) != 0
+	if schedule.domStar || schedule.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
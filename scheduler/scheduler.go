@@ -0,0 +1,333 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/CloudyKit/framework/container"
+	"github.com/CloudyKit/framework/request"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+var SchedulerType = reflect.TypeOf((*Scheduler)(nil))
+
+// GetScheduler returns the scheduler provided by the registry or nil
+func GetScheduler(registry *container.Registry) *Scheduler {
+	scheduler, _ := registry.LoadType(SchedulerType).(*Scheduler)
+	return scheduler
+}
+
+// Job a unit of background work, registry is forked from the scheduler registry for each run
+// so that the job can inject services
+type Job interface {
+	Run(ctx context.Context, registry *container.Registry) error
+}
+
+// JobFunc func implementing Job interface
+type JobFunc func(ctx context.Context, registry *container.Registry) error
+
+func (fn JobFunc) Run(ctx context.Context, registry *container.Registry) error {
+	return fn(ctx, registry)
+}
+
+// JobStatus the state of a scheduled job
+type JobStatus struct {
+	Name      string    `json:"name"`
+	Schedule  string    `json:"schedule"`
+	Running   bool      `json:"running"`
+	Runs      int64     `json:"runs"`
+	Failures  int64     `json:"failures"`
+	Skipped   int64     `json:"skipped"` // Skipped runs skipped because the previous run was not finished
+	LastStart time.Time `json:"last_start"`
+	LastEnd   time.Time `json:"last_end"`
+	LastError string    `json:"last_error,omitempty"`
+	Next      time.Time `json:"next"`
+}
+
+type scheduledJob struct {
+	job      Job
+	schedule Schedule
+
+	mx     sync.Mutex
+	status JobStatus
+}
+
+var (
+	// ErrJobExists is returned when adding a job with a name already in use
+	ErrJobExists = errors.New("scheduler: job already exists")
+	// ErrNotStarted is returned by Run when the scheduler is not started or was stopped
+	ErrNotStarted = errors.New("scheduler: not started")
+)
+
+// cycle the state of a Start/Stop cycle, each Start creates a new cycle so that a Stop
+// waiting for the goroutines of a previous cycle doesn't share the wait groups of the next one
+type cycle struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	loops   sync.WaitGroup
+	running sync.WaitGroup
+}
+
+// Scheduler runs jobs on their schedules, a job doesn't run again while the previous run is not finished
+type Scheduler struct {
+	Registry *container.Registry // Registry forked for each run
+	Location *time.Location      // Location of the cron expressions, defaults to time.Local
+
+	mx    sync.Mutex
+	jobs  map[string]*scheduledJob
+	cycle *cycle
+}
+
+// New creates a scheduler forking registry for the job runs
+func New(registry *container.Registry) *Scheduler {
+	return &Scheduler{Registry: registry, jobs: map[string]*scheduledJob{}}
+}
+
+// Add adds a job running on the cron expression, see ParseCron
+func (scheduler *Scheduler) Add(name, expression string, job Job) error {
+	schedule, err := ParseCron(expression, scheduler.Location)
+	if err != nil {
+		return err
+	}
+	return scheduler.add(name, expression, schedule, job)
+}
+
+// AddFunc adds a func job running on the cron expression, see ParseCron
+func (scheduler *Scheduler) AddFunc(name, expression string, fn func(ctx context.Context, registry *container.Registry) error) error {
+	return scheduler.Add(name, expression, JobFunc(fn))
+}
+
+// Every adds a job running each interval
+func (scheduler *Scheduler) Every(name string, interval time.Duration, job Job) error {
+	return scheduler.add(name, "@every "+interval.String(), Every(interval), job)
+}
+
+// AddSchedule adds a job running on a custom schedule
+func (scheduler *Scheduler) AddSchedule(name string, schedule Schedule, job Job) error {
+	return scheduler.add(name, fmt.Sprintf("%v", schedule), schedule, job)
+}
+
+func (scheduler *Scheduler) add(name, spec string, schedule Schedule, job Job) error {
+	scheduler.mx.Lock()
+	defer scheduler.mx.Unlock()
+
+	if _, found := scheduler.jobs[name]; found {
+		return fmt.Errorf("%w: %s", ErrJobExists, name)
+	}
+	if scheduler.jobs == nil {
+		scheduler.jobs = map[string]*scheduledJob{}
+	}
+
+	scheduled := &scheduledJob{job: job, schedule: schedule, status: JobStatus{Name: name, Schedule: spec}}
+	scheduler.jobs[name] = scheduled
+	if scheduler.cycle != nil {
+		scheduler.loop(scheduler.cycle, scheduled)
+	}
+	return nil
+}
+
+// Start starts the job loops, jobs added after Start are started immediately, see app.Starter
+func (scheduler *Scheduler) Start(_ context.Context) error {
+	scheduler.mx.Lock()
+	defer scheduler.mx.Unlock()
+
+	if scheduler.cycle != nil {
+		return nil
+	}
+	current := new(cycle)
+	current.ctx, current.cancel = context.WithCancel(context.Background())
+	scheduler.cycle = current
+	for _, scheduled := range scheduler.jobs {
+		scheduler.loop(current, scheduled)
+	}
+	return nil
+}
+
+// Stop stops the job loops, cancels the context of the running jobs and waits them to
+// finish until ctx is done, see app.Stopper
+func (scheduler *Scheduler) Stop(ctx context.Context) error {
+	scheduler.mx.Lock()
+	current := scheduler.cycle
+	scheduler.cycle = nil
+	scheduler.mx.Unlock()
+
+	if current == nil {
+		return nil
+	}
+	current.cancel()
+
+	// no run is added to the cycle once it is detached from the scheduler, see run
+	done := make(chan struct{})
+	go func() {
+		current.loops.Wait()
+		current.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("scheduler: waiting running jobs: %w", ctx.Err())
+	}
+}
+
+// Run runs the job name immediately, the run is skipped case the job is running, ErrNotStarted is
+// returned when the scheduler is not started, so that every run is canceled and waited by Stop
+func (scheduler *Scheduler) Run(name string) error {
+	scheduler.mx.Lock()
+	scheduled, found := scheduler.jobs[name]
+	current := scheduler.cycle
+	scheduler.mx.Unlock()
+
+	if !found {
+		return fmt.Errorf("scheduler: job %s not found", name)
+	}
+	if current == nil || !scheduler.run(current, scheduled) {
+		return ErrNotStarted
+	}
+	return nil
+}
+
+// Status returns the status of the job name
+func (scheduler *Scheduler) Status(name string) (JobStatus, bool) {
+	scheduler.mx.Lock()
+	scheduled, found := scheduler.jobs[name]
+	scheduler.mx.Unlock()
+
+	if !found {
+		return JobStatus{}, false
+	}
+	scheduled.mx.Lock()
+	defer scheduled.mx.Unlock()
+	return scheduled.status, true
+}
+
+// Statuses returns the status of all the jobs sorted by name
+func (scheduler *Scheduler) Statuses() []JobStatus {
+	scheduler.mx.Lock()
+	statuses := make([]JobStatus, 0, len(scheduler.jobs))
+	for _, scheduled := range scheduler.jobs {
+		scheduled.mx.Lock()
+		statuses = append(statuses, scheduled.status)
+		scheduled.mx.Unlock()
+	}
+	scheduler.mx.Unlock()
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// loop runs scheduled on its schedule until the cycle is stopped, called with the scheduler locked
+func (scheduler *Scheduler) loop(current *cycle, scheduled *scheduledJob) {
+	ctx := current.ctx
+	current.loops.Add(1)
+	go func() {
+		defer current.loops.Done()
+		for {
+			next := scheduled.schedule.Next(time.Now())
+			scheduled.mx.Lock()
+			scheduled.status.Next = next
+			scheduled.mx.Unlock()
+			if next.IsZero() {
+				return
+			}
+
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				scheduler.run(current, scheduled)
+			}
+		}
+	}()
+}
+
+// run runs the job in background in the cycle, the run is skipped case the previous run is not finished,
+// false is returned when the cycle was stopped
+func (scheduler *Scheduler) run(current *cycle, scheduled *scheduledJob) bool {
+	// the run is added while the cycle is attached, so Stop never waits while a run is being added
+	scheduler.mx.Lock()
+	defer scheduler.mx.Unlock()
+	if scheduler.cycle != current {
+		return false
+	}
+
+	scheduled.mx.Lock()
+	if scheduled.status.Running {
+		scheduled.status.Skipped++
+		scheduled.mx.Unlock()
+		return true
+	}
+	scheduled.status.Running = true
+	scheduled.status.LastStart = time.Now()
+	scheduled.mx.Unlock()
+
+	current.running.Add(1)
+	go func() {
+		defer current.running.Done()
+		err := scheduler.execute(current.ctx, scheduled)
+
+		scheduled.mx.Lock()
+		scheduled.status.Running = false
+		scheduled.status.LastEnd = time.Now()
+		scheduled.status.Runs++
+		scheduled.status.LastError = ""
+		if err != nil {
+			scheduled.status.Failures++
+			scheduled.status.LastError = err.Error()
+		}
+		name := scheduled.status.Name
+		scheduled.mx.Unlock()
+
+		if err != nil {
+			request.GetLogger(scheduler.Registry).Error("scheduler: job failed", "job", name, "error", err)
+		}
+	}()
+	return true
+}
+
+// execute runs the job with a forked registry, panics are returned as errors
+func (scheduler *Scheduler) execute(ctx context.Context, scheduled *scheduledJob) (err error) {
+	registry := scheduler.Registry
+	if registry == nil {
+		registry = container.New()
+	} else {
+		registry = registry.Fork()
+	}
+	defer registry.Dispose()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("scheduler: job panicked: %v", recovered)
+		}
+	}()
+	return scheduled.job.Run(ctx, registry)
+}
@@ -0,0 +1,185 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"github.com/CloudyKit/framework/app"
+	"github.com/CloudyKit/framework/container"
+	"github.com/CloudyKit/framework/request"
+	"github.com/CloudyKit/framework/tdutils"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	location := time.UTC
+	from := time.Date(2024, time.January, 31, 10, 7, 30, 0, location) // wednesday
+
+	for _, test := range []struct {
+		expression string
+		next       time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 31, 10, 8, 0, 0, location)},
+		{"*/15 * * * *", time.Date(2024, time.January, 31, 10, 15, 0, 0, location)},
+		{"0 9-18 * * mon-fri", time.Date(2024, time.January, 31, 11, 0, 0, 0, location)},
+		{"30 8 * * sat,7", time.Date(2024, time.February, 3, 8, 30, 0, 0, location)},
+		{"0 0 29 feb *", time.Date(2024, time.February, 29, 0, 0, 0, 0, location)},
+		{"0 0 1 * 1", time.Date(2024, time.February, 1, 0, 0, 0, 0, location)},
+		{"@monthly", time.Date(2024, time.February, 1, 0, 0, 0, 0, location)},
+		{"@every 90s", from.Add(90 * time.Second)},
+		{"0 0 30 2 *", time.Time{}},
+	} {
+		schedule, err := ParseCron(test.expression, location)
+		if err != nil {
+			t.Errorf("%s: %v", test.expression, err)
+			continue
+		}
+		if next := schedule.Next(from); !next.Equal(test.next) {
+			t.Errorf("%s: next run %s, want %s", test.expression, next, test.next)
+		}
+	}
+
+	for _, expression := range []string{"* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "@every x"} {
+		if _, err := ParseCron(expression, location); err == nil {
+			t.Errorf("%s: expected an error", expression)
+		}
+	}
+}
+
+type counterService struct {
+	runs int32
+}
+
+func TestComponent(t *testing.T) {
+	kernel := app.New()
+	service := &counterService{}
+	kernel.Registry.WithValues(service)
+
+	component := &Component{}
+	kernel.Bootstrap(component)
+
+	release := make(chan struct{})
+	scheduler := GetScheduler(kernel.Registry)
+	err := scheduler.Every("slow", 5*time.Millisecond, JobFunc(func(ctx context.Context, registry *container.Registry) error {
+		var injected *counterService
+		registry.Load(&injected)
+		atomic.AddInt32(&injected.runs, 1)
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return errors.New("slow failed")
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := scheduler.AddFunc("slow", "@hourly", nil); !errors.Is(err, ErrJobExists) {
+		t.Errorf("expected ErrJobExists got %v", err)
+	}
+
+	if err := kernel.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	status, _ := scheduler.Status("slow")
+	if !status.Running || status.Skipped == 0 || atomic.LoadInt32(&service.runs) != 1 {
+		t.Errorf("overlapping runs should be skipped, got %+v runs %d", status, service.runs)
+	}
+
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := kernel.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	status, _ = scheduler.Status("slow")
+	if status.Running || status.Runs == 0 || status.Failures != status.Runs || status.LastError != "slow failed" {
+		t.Errorf("unexpected status after stop %+v", status)
+	}
+}
+
+func TestComponentStatus(t *testing.T) {
+	kernel := app.New()
+	kernel.Bootstrap(&Component{StatusPath: "/jobs", StatusFilters: []request.Handler{request.HandlerFunc(func(c *request.Context) {
+		if c.Request.Header.Get("Authorization") == "" {
+			c.Response.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	})}})
+	GetScheduler(kernel.Registry).Every("cleanup", time.Hour, JobFunc(func(ctx context.Context, registry *container.Registry) error {
+		return nil
+	}))
+
+	tester := tdutils.NewHTTPTester(t, kernel)
+	tester.GetRequest("/jobs").ExpectStatus(http.StatusUnauthorized, "status filters should run before the status endpoint")
+
+	r, _ := http.NewRequest("GET", "/jobs", nil)
+	r.Header.Set("Authorization", "token")
+	tester.Request(r).ExpectStatus(http.StatusOK, "status endpoint should respond").
+		ExpectOutputContains(`"cleanup"`, "status endpoint should list the jobs")
+}
+
+func TestRunCycles(t *testing.T) {
+	scheduler := New(container.New())
+	var runs int32
+	err := scheduler.AddFunc("manual", "@yearly", func(ctx context.Context, registry *container.Registry) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := scheduler.Run("manual"); !errors.Is(err, ErrNotStarted) {
+		t.Errorf("expected ErrNotStarted before start got %v", err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		if err := scheduler.Start(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if err := scheduler.Run("manual"); err != nil {
+			t.Fatal(err)
+		}
+		if err := scheduler.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if got := atomic.LoadInt32(&runs); got != int32(i) {
+			t.Errorf("cycle %d: expected %d runs got %d", i, i, got)
+		}
+	}
+
+	if err := scheduler.Run("manual"); !errors.Is(err, ErrNotStarted) {
+		t.Errorf("expected ErrNotStarted after stop got %v", err)
+	}
+	if err := scheduler.Run("missing"); err == nil || errors.Is(err, ErrNotStarted) {
+		t.Errorf("expected not found error got %v", err)
+	}
+}
@@ -25,8 +25,8 @@ package session
 import (
 	"github.com/CloudyKit/framework/app"
 	"github.com/CloudyKit/framework/container"
+	"github.com/CloudyKit/framework/health"
 	"github.com/CloudyKit/framework/request"
-	"log"
 	"net/http"
 	"reflect"
 )
@@ -34,6 +34,9 @@ import (
 type Bundle struct {
 	CookieOptions *CookieOptions
 	Manager       *Manager
+	// HealthCheck names the readiness check of the session store, the check is not registered
+	// when empty, ex: "session", see health.Checks
+	HealthCheck string
 }
 
 var (
@@ -58,7 +61,7 @@ func (component *Bundle) Handle(ctx *request.Context) {
 		}
 		err := component.Manager.Open(ctx.Registry, readedcookie.Value, &s.data) //todo: use this error message here can be helpful
 		if err != nil {
-			log.Println("Session read err:", err.Error())
+			ctx.Logger().Error("session read", "error", err)
 		}
 	}
 
@@ -90,7 +93,7 @@ func (component *Bundle) Handle(ctx *request.Context) {
 	_sessionPool.Put(s)
 
 	if err != nil {
-		log.Println("Session write err:", err.Error())
+		ctx.Logger().Error("session write", "error", err)
 	}
 }
 
@@ -108,4 +111,13 @@ func (component *Bundle) Bootstrap(a *app.Kernel) {
 	}
 
 	app.GetKernel(a.Registry).BindFilterHandlers(component)
+
+	if component.HealthCheck != "" {
+		health.GetChecks(a.Registry).Add(component.HealthCheck, component.Manager)
+	}
+
+	// stops the garbage collector when the kernel is shutting down
+	a.Subscribe("hub.shutdown", func(*app.ShutdownEvent) {
+		component.Manager.Stop()
+	})
 }
@@ -23,6 +23,7 @@
 package session
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/gob"
@@ -48,6 +49,11 @@ type Store interface {
 	GC(c *container.Registry, before time.Time)
 }
 
+// Pinger is implemented by stores able to report if they are reachable, see Manager.Check
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
 type RandGenerator struct{}
 type GobSerializer struct{}
 
@@ -23,8 +23,10 @@
 package session
 
 import (
+	"context"
 	"github.com/CloudyKit/framework/concurrent"
 	"github.com/CloudyKit/framework/container"
+	"sync"
 	"time"
 )
 
@@ -56,14 +58,38 @@ type Manager struct {
 	Duration   time.Duration
 	gcEvery    time.Duration
 	kMX        *concurrent.KeyLocker
+	stop       chan struct{}
+	stopOnce   sync.Once
 }
 
 func (manager *Manager) gcgoroutine() {
-	for n := range time.NewTicker(manager.gcEvery).C {
-		manager.Store.GC(manager.Global, n.Add(-manager.Duration))
+	ticker := time.NewTicker(manager.gcEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case n := <-ticker.C:
+			manager.Store.GC(manager.Global, n.Add(-manager.Duration))
+		case <-manager.stop:
+			return
+		}
 	}
 }
 
+// Stop stops the garbage collect goroutine, calling Stop more than once has no effect
+func (manager *Manager) Stop() {
+	manager.stopOnce.Do(func() {
+		close(manager.stop)
+	})
+}
+
+// Check reports if the store is reachable, stores not implementing Pinger are assumed reachable, see health.Checker
+func (manager *Manager) Check(ctx context.Context) error {
+	if pinger, ok := manager.Store.(Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
 // Open load stored session and un serialize the stored data into dst
 func (manager *Manager) Open(ctx *container.Registry, sessionName string, dst interface{}) error {
 	defer manager.kMX.Lock(sessionName).Unlock()
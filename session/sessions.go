@@ -60,6 +60,7 @@ func New(gcEvery time.Duration, duration time.Duration, store Store, serializer
 		Store:      store,
 		Serializer: serializer,
 		kMX:        concurrent.NewKeyLocker(),
+		stop:       make(chan struct{}),
 	}
 
 	//collect expired sessions
@@ -23,6 +23,8 @@
 package file
 
 import (
+	"context"
+	"errors"
 	"github.com/CloudyKit/framework/container"
 	"io"
 	"io/ioutil"
@@ -40,7 +42,7 @@ func New(directory string) store {
 	directory, _ = filepath.Abs(directory)
 	_, err := os.Stat(directory)
 	if err != nil && os.IsNotExist(err) {
-		os.MkdirAll(directory, 0666)
+		os.MkdirAll(directory, 0755)
 	}
 	return store{directory}
 }
@@ -89,3 +91,20 @@ func (store store) GC(_ *container.Registry, before time.Time) {
 		}
 	}
 }
+
+// Ping checks that the sessions directory exists and is writable
+func (store store) Ping(_ context.Context) error {
+	stat, err := os.Stat(store.BaseDir)
+	if err != nil {
+		return err
+	}
+	if !stat.IsDir() {
+		return errors.New("session/store/file: " + store.BaseDir + " is not a directory")
+	}
+	file, err := os.CreateTemp(store.BaseDir, ".ping")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
@@ -0,0 +1,64 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package file
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	store := New(filepath.Join(t.TempDir(), "sessions", "nested"))
+
+	stat, err := os.Stat(store.BaseDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.Mode().Perm()&0700 != 0700 {
+		t.Errorf("the sessions directory should be traversable by the owner, got %s", stat.Mode())
+	}
+	if err := store.Ping(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	writer, err := store.Writer(nil, "session-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(writer, "data")
+	writer.Close()
+
+	reader, err := store.Reader(nil, "session-id", time.Now().Add(-time.Minute))
+	if err != nil || reader == nil {
+		t.Fatalf("expected the session file got %v", err)
+	}
+	data, _ := io.ReadAll(reader)
+	reader.Close()
+	if string(data) != "data" {
+		t.Errorf("unexpected session data %q", data)
+	}
+}
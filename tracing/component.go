@@ -0,0 +1,150 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"github.com/CloudyKit/framework/app"
+	"github.com/CloudyKit/framework/container"
+	"github.com/CloudyKit/framework/event"
+	"github.com/CloudyKit/framework/request"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Component starts a span per request continuing the W3C traceparent of the request, a child span
+// for each filter and handler of the routes added after it, and a child span for each event dispatched
+// in the request, the view renderer adds its spans to the request span, see ODMObserver for the odm spans.
+type Component struct {
+	Tracer *Tracer // Tracer defaults to a tracer without exporters
+}
+
+func (component *Component) Bootstrap(a *app.Kernel) {
+	if component.Tracer == nil {
+		component.Tracer = NewTracer()
+	}
+
+	a.Registry.WithTypeAndValue(TracerType, component.Tracer)
+	app.GetKernel(a.Registry).BindFilterHandlers(component)
+
+	event.GetDispatcher(a.Registry).Intercept(func(registry *container.Registry, eventName string, dispatch func() (bool, error)) (bool, error) {
+		span := StartRegistrySpan(registry, "event "+eventName)
+		canceled, err := dispatch()
+		if canceled {
+			span.SetAttribute("event.canceled", true)
+		}
+		span.SetError(err)
+		span.End()
+		return canceled, err
+	})
+}
+
+// ODMObserver traces the odm manager operations as children of the request span provided by the
+// manager registry, see odm.Observer
+type ODMObserver struct{}
+
+func (ODMObserver) ObserveOperation(registry *container.Registry, collection, operation string) func(err error) {
+	span := StartRegistrySpan(registry, "odm."+operation)
+	span.SetAttribute("odm.collection", collection)
+	return func(err error) {
+		span.SetError(err)
+		span.End()
+	}
+}
+
+// Stop closes the exporters implementing io.Closer, see app.Stopper
+func (component *Component) Stop(ctx context.Context) error {
+	var errs []error
+	for _, exporter := range component.Tracer.Exporters {
+		if closer, ok := exporter.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (component *Component) Handle(c *request.Context) {
+	parent, _ := ParseTraceparent(c.Request.Header.Get("traceparent"))
+
+	name := c.Name
+	if name == "" {
+		name = c.Request.Method + " " + c.Request.URL.Path
+	}
+
+	span := component.Tracer.Start(name, parent)
+	span.SetAttribute("http.method", c.Request.Method)
+	span.SetAttribute("http.route", c.Name)
+	span.SetAttribute("http.target", c.Request.URL.RequestURI())
+
+	c.Request = c.Request.WithContext(ContextWithSpan(c.Request.Context(), span))
+	c.Registry.WithTypeAndValue(SpanType, span)
+
+	writer := &request.StatusWriter{ResponseWriter: c.Response}
+	c.Response = writer
+
+	c.WrapHandlers(func(handler request.Handler) request.Handler {
+		return &handlerSpan{handler: handler, name: request.HandlerName(handler)}
+	})
+
+	completed := false
+	defer func() {
+		c.Response = writer.ResponseWriter
+		status := writer.Status
+		if status == 0 {
+			status = http.StatusOK
+			if !completed {
+				status = http.StatusInternalServerError
+				span.SetError(errors.New("panic"))
+			}
+		}
+		span.SetAttribute("http.status_code", strconv.Itoa(status))
+		span.End()
+	}()
+
+	c.Next()
+	completed = true
+}
+
+// handlerSpan runs handler within a child span of the current span of the request
+type handlerSpan struct {
+	handler request.Handler
+	name    string
+}
+
+func (h *handlerSpan) Handle(c *request.Context) {
+	parent := GetSpan(c.Registry)
+	span := parent.StartChild(h.name)
+
+	r := c.Request
+	c.Request = r.WithContext(ContextWithSpan(r.Context(), span))
+	c.Registry.WithTypeAndValue(SpanType, span)
+	defer func() {
+		c.Request = r
+		c.Registry.WithTypeAndValue(SpanType, parent)
+		span.End()
+	}()
+
+	h.handler.Handle(c)
+}
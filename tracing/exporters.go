@@ -0,0 +1,94 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tracing
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// MemoryExporter keeps the finished spans in memory, ex: assert the spans in tests
+type MemoryExporter struct {
+	mx    sync.Mutex
+	spans []SpanData
+}
+
+func (exporter *MemoryExporter) Export(span SpanData) error {
+	exporter.mx.Lock()
+	exporter.spans = append(exporter.spans, span)
+	exporter.mx.Unlock()
+	return nil
+}
+
+// Spans returns the finished spans in the order they ended
+func (exporter *MemoryExporter) Spans() []SpanData {
+	exporter.mx.Lock()
+	defer exporter.mx.Unlock()
+	return append([]SpanData(nil), exporter.spans...)
+}
+
+// Reset drops the recorded spans
+func (exporter *MemoryExporter) Reset() {
+	exporter.mx.Lock()
+	exporter.spans = nil
+	exporter.mx.Unlock()
+}
+
+// JSONLinesExporter writes each finished span as a json line
+type JSONLinesExporter struct {
+	mx      sync.Mutex
+	encoder *json.Encoder
+	closer  io.Closer
+}
+
+// NewJSONLinesExporter creates an exporter writing into w
+func NewJSONLinesExporter(w io.Writer) *JSONLinesExporter {
+	return &JSONLinesExporter{encoder: json.NewEncoder(w)}
+}
+
+// OpenJSONLinesFile creates an exporter appending to the file fileName, the file is closed by Close
+func OpenJSONLinesFile(fileName string) (*JSONLinesExporter, error) {
+	file, err := os.OpenFile(fileName, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	exporter := NewJSONLinesExporter(file)
+	exporter.closer = file
+	return exporter, nil
+}
+
+func (exporter *JSONLinesExporter) Export(span SpanData) error {
+	exporter.mx.Lock()
+	defer exporter.mx.Unlock()
+	return exporter.encoder.Encode(span)
+}
+
+// Close closes the file opened by OpenJSONLinesFile
+func (exporter *JSONLinesExporter) Close() error {
+	if exporter.closer == nil {
+		return nil
+	}
+	return exporter.closer.Close()
+}
@@ -0,0 +1,295 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/CloudyKit/framework/container"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	TracerType = reflect.TypeOf((*Tracer)(nil))
+	SpanType   = reflect.TypeOf((*Span)(nil))
+)
+
+// TraceID identifies a trace
+type TraceID [16]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// SpanID identifies a span within a trace
+type SpanID [8]byte
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// SpanContext the identity of a span propagated across services, see ParseTraceparent
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Traceparent formats the span context as a W3C traceparent header
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// ParseTraceparent parses a W3C traceparent header, ex: 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func ParseTraceparent(header string) (sc SpanContext, ok bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return
+	}
+	if len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return
+	}
+
+	var flags [1]byte
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return SpanContext{}, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return SpanContext{}, false
+	}
+	if _, err := hex.Decode(flags[:], []byte(parts[3])); err != nil {
+		return SpanContext{}, false
+	}
+	sc.Sampled = flags[0]&1 == 1
+	return sc, sc.IsValid()
+}
+
+// SpanData the recorded data of a finished span sent to the exporters
+type SpanData struct {
+	TraceID    string            `json:"trace_id"`
+	SpanID     string            `json:"span_id"`
+	ParentID   string            `json:"parent_id,omitempty"`
+	Name       string            `json:"name"`
+	Start      time.Time         `json:"start"`
+	End        time.Time         `json:"end"`
+	Duration   time.Duration     `json:"duration"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// Exporter receives the finished spans, ex: MemoryExporter, JSONLinesExporter
+type Exporter interface {
+	Export(span SpanData) error
+}
+
+// Tracer starts root spans and sends the finished spans to the exporters
+type Tracer struct {
+	Exporters []Exporter
+}
+
+// NewTracer creates a tracer exporting to exporters
+func NewTracer(exporters ...Exporter) *Tracer {
+	return &Tracer{Exporters: exporters}
+}
+
+// GetTracer returns the tracer provided by the registry or nil
+func GetTracer(registry *container.Registry) *Tracer {
+	tracer, _ := registry.LoadType(TracerType).(*Tracer)
+	return tracer
+}
+
+// Start starts a root span, case parent is valid the span continues the remote trace of parent
+// and is exported only when parent is sampled
+func (tracer *Tracer) Start(name string, parent SpanContext) *Span {
+	span := &Span{tracer: tracer, sampled: true, data: SpanData{Name: name, Start: time.Now()}}
+	span.context.SpanID = newSpanID()
+	if parent.IsValid() {
+		span.context.TraceID = parent.TraceID
+		span.data.ParentID = parent.SpanID.String()
+		span.sampled = parent.Sampled
+	} else {
+		_, _ = rand.Read(span.context.TraceID[:])
+	}
+	span.context.Sampled = span.sampled
+	span.data.TraceID = span.context.TraceID.String()
+	span.data.SpanID = span.context.SpanID.String()
+	return span
+}
+
+func (tracer *Tracer) export(data SpanData) {
+	for _, exporter := range tracer.Exporters {
+		if err := exporter.Export(data); err != nil {
+			slog.Default().Error("tracing: export span", "span", data.Name, "error", err)
+		}
+	}
+}
+
+func newSpanID() (id SpanID) {
+	_, _ = rand.Read(id[:])
+	return
+}
+
+// Span a timed operation within a trace, the methods of a nil span do nothing so instrumented code
+// runs without checks when tracing is not enabled
+type Span struct {
+	tracer  *Tracer
+	context SpanContext
+	sampled bool
+
+	mx    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// Context returns the span context, see SpanContext.Traceparent
+func (span *Span) Context() SpanContext {
+	if span == nil {
+		return SpanContext{}
+	}
+	return span.context
+}
+
+// StartChild starts a child span
+func (span *Span) StartChild(name string) *Span {
+	if span == nil {
+		return nil
+	}
+	return span.tracer.Start(name, span.context)
+}
+
+// SetAttribute sets an attribute of the span, attributes set after End are ignored
+func (span *Span) SetAttribute(key string, value interface{}) {
+	if span == nil {
+		return
+	}
+	span.mx.Lock()
+	if span.ended {
+		span.mx.Unlock()
+		return
+	}
+	if span.data.Attributes == nil {
+		span.data.Attributes = map[string]string{}
+	}
+	span.data.Attributes[key] = fmt.Sprint(value)
+	span.mx.Unlock()
+}
+
+// SetError records err in the span, nil errors and errors set after End are ignored
+func (span *Span) SetError(err error) {
+	if span == nil || err == nil {
+		return
+	}
+	span.mx.Lock()
+	if !span.ended {
+		span.data.Error = err.Error()
+	}
+	span.mx.Unlock()
+}
+
+// End finishes the span and exports it, calling End more than once has no effect
+func (span *Span) End() {
+	if span == nil {
+		return
+	}
+	span.mx.Lock()
+	if span.ended {
+		span.mx.Unlock()
+		return
+	}
+	span.ended = true
+	span.data.End = time.Now()
+	span.data.Duration = span.data.End.Sub(span.data.Start)
+	// the exported data doesn't share the attributes map with the span
+	data := span.data
+	if data.Attributes != nil {
+		data.Attributes = make(map[string]string, len(span.data.Attributes))
+		for key, value := range span.data.Attributes {
+			data.Attributes[key] = value
+		}
+	}
+	span.mx.Unlock()
+
+	if span.sampled {
+		span.tracer.export(data)
+	}
+}
+
+type spanKey struct{}
+
+// ContextWithSpan returns a copy of ctx carrying span
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns the span carried by ctx or nil
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// StartSpan starts a child of the span carried by ctx, returning a copy of ctx carrying the child,
+// without a span in ctx the returned span is nil
+func StartSpan(ctx context.Context, name string) (context.Context, *Span) {
+	span := SpanFromContext(ctx).StartChild(name)
+	if span == nil {
+		return ctx, nil
+	}
+	return ContextWithSpan(ctx, span), span
+}
+
+// GetSpan returns the current span of the request registry or nil
+func GetSpan(registry *container.Registry) *Span {
+	if registry == nil {
+		return nil
+	}
+	span, _ := registry.LoadType(SpanType).(*Span)
+	return span
+}
+
+// StartRegistrySpan starts a child of the current span of the request registry, without a span
+// in the registry the returned span is nil, ex: used by the layers without access to the request context
+func StartRegistrySpan(registry *container.Registry, name string) *Span {
+	return GetSpan(registry).StartChild(name)
+}
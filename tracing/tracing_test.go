@@ -0,0 +1,148 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/CloudyKit/framework/app"
+	"github.com/CloudyKit/framework/event"
+	"github.com/CloudyKit/framework/request"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	sc, ok := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if !ok || sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID.String() != "00f067aa0ba902b7" || !sc.Sampled {
+		t.Errorf("unexpected span context %+v %v", sc, ok)
+	}
+	if sc.Traceparent() != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Errorf("unexpected traceparent %s", sc.Traceparent())
+	}
+
+	for _, header := range []string{
+		"",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01",
+	} {
+		if _, ok := ParseTraceparent(header); ok {
+			t.Errorf("traceparent %q should be invalid", header)
+		}
+	}
+}
+
+type testPayload struct {
+	event.Event
+}
+
+func TestComponent(t *testing.T) {
+	exporter := new(MemoryExporter)
+	kernel := app.New()
+	kernel.Bootstrap(&Component{Tracer: NewTracer(exporter)})
+
+	kernel.AddHandlerName("users.show", "GET", "/users/:id", request.HandlerFunc(func(c *request.Context) {
+		_, span := StartSpan(c.Context(), "load user")
+		span.End()
+		_, _ = event.Dispatch(c.Registry, "users.loaded", &testPayload{})
+		c.WriteString("user")
+	}))
+
+	r := httptest.NewRequest("GET", "/users/1", nil)
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	kernel.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := exporter.Spans()
+	if len(spans) != 4 {
+		t.Fatalf("expected 4 spans got %+v", spans)
+	}
+
+	byName := map[string]SpanData{}
+	for _, span := range spans {
+		if span.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("span %s should continue the incoming trace", span.Name)
+		}
+		byName[span.Name] = span
+	}
+
+	root := byName["users.show"]
+	if root.ParentID != "00f067aa0ba902b7" || root.Attributes["http.status_code"] != "200" {
+		t.Errorf("unexpected request span %+v", root)
+	}
+
+	handler := spans[2]
+	if handler.ParentID != root.SpanID {
+		t.Errorf("handler span should be a child of the request span: %+v", handler)
+	}
+	if byName["load user"].ParentID != handler.SpanID || byName["event users.loaded"].ParentID != handler.SpanID {
+		t.Errorf("spans started by the handler should be children of the handler span: %+v", spans)
+	}
+
+	exporter.Reset()
+	r = httptest.NewRequest("GET", "/users/1", nil)
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	kernel.ServeHTTP(httptest.NewRecorder(), r)
+	if spans := exporter.Spans(); len(spans) != 0 {
+		t.Errorf("not sampled traces should not be exported, got %+v", spans)
+	}
+}
+
+func TestJSONLinesExporter(t *testing.T) {
+	var output bytes.Buffer
+	tracer := NewTracer(NewJSONLinesExporter(&output))
+
+	span := tracer.Start("job", SpanContext{})
+	span.SetAttribute("queue", "mail")
+	span.SetError(http.ErrHandlerTimeout)
+	span.End()
+	span.End()
+
+	var data SpanData
+	decoder := json.NewDecoder(&output)
+	if err := decoder.Decode(&data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Name != "job" || data.Attributes["queue"] != "mail" || data.Error != http.ErrHandlerTimeout.Error() || data.ParentID != "" {
+		t.Errorf("unexpected span %+v", data)
+	}
+	if decoder.More() {
+		t.Errorf("span should be exported once")
+	}
+}
+
+func TestSpanEnded(t *testing.T) {
+	exporter := new(MemoryExporter)
+	span := NewTracer(exporter).Start("job", SpanContext{})
+	span.SetAttribute("queue", "mail")
+	span.End()
+	span.SetAttribute("queue", "sms")
+	span.SetError(http.ErrHandlerTimeout)
+
+	spans := exporter.Spans()
+	if len(spans) != 1 || spans[0].Attributes["queue"] != "mail" || spans[0].Error != "" {
+		t.Errorf("the exported span should not change after End, got %+v", spans)
+	}
+}
@@ -23,9 +23,11 @@
 package view
 
 import (
+	"errors"
 	"github.com/CloudyKit/framework/app"
 	"github.com/CloudyKit/framework/container"
 	"github.com/CloudyKit/framework/request"
+	"github.com/CloudyKit/framework/tracing"
 	"github.com/CloudyKit/jet/v6"
 	"reflect"
 )
@@ -51,6 +53,18 @@ func Render(global *container.Registry, viewName string, c interface{}) {
 	GetRenderer(global).Render(viewName, c)
 }
 
+// Template returns a responder rendering the template with the context, actions can return it
+// as the response, ex: return view.Template("users/show.jet", user), nil
+func Template(templateName string, context interface{}) app.Responder {
+	return app.ResponderFunc(func(c *request.Context) error {
+		renderer := GetRenderer(c.Registry)
+		if renderer == nil {
+			return errors.New("view: renderer is not available in the registry")
+		}
+		return renderer.render(templateName, context)
+	})
+}
+
 var JetSetType = reflect.TypeOf((*jet.Set)(nil))
 var globalType = reflect.TypeOf(Globals(nil))
 
@@ -92,7 +106,7 @@ func (renderer *Renderer) render(templateName string, context interface{}) error
 	if err != nil {
 		return err
 	}
-	return t.Execute(renderer.rcontext.Response, renderer.scope, context)
+	return renderer.Execute(t, context)
 }
 
 func (renderer *Renderer) Render(templateName string, context interface{}) {
@@ -101,8 +115,14 @@ func (renderer *Renderer) Render(templateName string, context interface{}) {
 	}
 }
 
+// Execute executes the template t, the execution is traced as a child of the request span, see tracing.Component
 func (renderer *Renderer) Execute(t *jet.Template, context interface{}) error {
-	return t.Execute(renderer.rcontext.Response, renderer.scope, context)
+	span := tracing.StartRegistrySpan(renderer.rcontext.Registry, "view.render")
+	span.SetAttribute("view.template", t.Name)
+	err := t.Execute(renderer.rcontext.Response, renderer.scope, context)
+	span.SetError(err)
+	span.End()
+	return err
 }
 
 func (renderer *Renderer) WithValue(name string, v reflect.Value) *Renderer {
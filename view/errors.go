@@ -0,0 +1,92 @@
+// MIT License
+//
+// Copyright (c) 2017 José Santos <henrique_1609@me.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package view
+
+import (
+	"errors"
+	"github.com/CloudyKit/framework/request"
+	"github.com/CloudyKit/jet/v6"
+	"net/http"
+	"path"
+	"strconv"
+)
+
+// ErrorPage is the context passed to error page templates
+type ErrorPage struct {
+	Status  int
+	Message string
+	Err     error
+}
+
+// ErrorPages renders html error responses with jet templates, templates are looked up in Dir
+// as "<status>.jet" falling back to "default.jet", see app.ErrorHandler.Pages
+type ErrorPages struct {
+	Set *jet.Set // Set defaults to the jet set available in the registry
+	Dir string   // Dir defaults to "errors"
+}
+
+func (pages *ErrorPages) template(set *jet.Set, status int) (*jet.Template, error) {
+	dir := pages.Dir
+	if dir == "" {
+		dir = "errors"
+	}
+	t, err := set.GetTemplate(path.Join(dir, strconv.Itoa(status)+".jet"))
+	if err != nil {
+		t, err = set.GetTemplate(path.Join(dir, "default.jet"))
+	}
+	return t, err
+}
+
+func (pages *ErrorPages) HandleError(c *request.Context, err error) {
+	page := &ErrorPage{Status: request.StatusOf(err), Err: err}
+
+	var statusErr *request.Error
+	if errors.As(err, &statusErr) {
+		page.Message = statusErr.Message
+	}
+	if page.Message == "" {
+		page.Message = http.StatusText(page.Status)
+	}
+
+	set := pages.Set
+	if set == nil {
+		set = GetJetSet(c.Registry)
+	}
+
+	var t *jet.Template
+	if set != nil {
+		t, _ = pages.template(set, page.Status)
+	}
+	if t == nil {
+		http.Error(c.Response, page.Message, page.Status)
+		return
+	}
+
+	c.Response.Header().Set("Content-Type", "text/html; charset=utf-8")
+	c.Response.WriteHeader(page.Status)
+	if renderer := GetRenderer(c.Registry); renderer != nil {
+		_ = renderer.Execute(t, page)
+	} else {
+		_ = t.Execute(c.Response, nil, page)
+	}
+}